@@ -0,0 +1,59 @@
+//go:build !windows
+// +build !windows
+
+package lifecycle
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// activationFDsEnv holds the number of sockets passed by systemd, which begin at file descriptor 3.
+	activationFDsEnv = "LISTEN_FDS"
+	// activationPIDEnv holds the process the sockets were passed to.
+	activationPIDEnv = "LISTEN_PID"
+	// activationNamesEnv holds the colon separated names of the sockets passed by systemd.
+	activationNamesEnv = "LISTEN_FDNAMES"
+)
+
+// activateListeners adopts the sockets passed to the process by systemd socket activation (see sd_listen_fds(3)). The
+// environment is cleared so the processes it starts do not inherit it.
+func (app *Application) activateListeners() {
+	pid, fds := os.Getenv(activationPIDEnv), os.Getenv(activationFDsEnv)
+	if pid != strconv.Itoa(os.Getpid()) || fds == "" {
+		return
+	}
+
+	_ = os.Unsetenv(activationPIDEnv)
+	_ = os.Unsetenv(activationFDsEnv)
+	_ = os.Unsetenv(activationNamesEnv)
+
+	n, err := strconv.Atoi(fds)
+	if err != nil {
+		app.report(newEvent(EventWarning, "", "", time.Now(), fmt.Errorf("invalid %s: %w", activationFDsEnv, err)))
+		return
+	}
+
+	for i := 0; i < n; i++ {
+		f := os.NewFile(uintptr(3+i), "activated")
+		listener, err := net.FileListener(f)
+		_ = f.Close()
+
+		// datagram sockets cannot be adopted as listeners
+		if err != nil {
+			app.report(newEvent(EventWarning, "", "", time.Now(), fmt.Errorf("failed to adopt activated socket: %w", err)))
+			continue
+		}
+
+		addr := listener.Addr()
+		managed := newManagedListener(listener, addr.Network(), addr.String())
+		managed.activated = true
+
+		app.sockets = append(app.sockets, managed)
+		app.deferred = append(app.deferred, owned[func() error]{value: managed.Close})
+	}
+}
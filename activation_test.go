@@ -0,0 +1,72 @@
+//go:build !windows
+// +build !windows
+
+package lifecycle
+
+import (
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// activationAddrEnv provides the activated test process the address of the socket it was passed.
+const activationAddrEnv = "LIFECYCLE_TEST_ACTIVATION_ADDR"
+
+func Test_ApplicationSocketActivation(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	f, err := listener.(*net.TCPListener).File()
+	require.NoError(t, err)
+	defer f.Close()
+
+	// like systemd, LISTEN_PID is set once the pid of the activated process is known
+	cmd := exec.Command("/bin/sh", "-c", `LISTEN_PID=$$ exec "$0" "$@"`,
+		os.Args[0], "-test.run=^Test_ApplicationSocketActivation_Process$")
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	cmd.ExtraFiles = []*os.File{f}
+	cmd.Env = append(os.Environ(), activationFDsEnv+"=1", activationAddrEnv+"="+listener.Addr().String())
+	require.NoError(t, cmd.Start())
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.SetDeadline(time.Now().Add(10*time.Second)))
+	reply, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	require.Equal(t, "activated", string(reply))
+	require.NoError(t, cmd.Wait())
+}
+
+// Test_ApplicationSocketActivation_Process is run as the process started by Test_ApplicationSocketActivation.
+func Test_ApplicationSocketActivation_Process(t *testing.T) {
+	addr, ok := os.LookupEnv(activationAddrEnv)
+	if !ok {
+		t.Skip("only run as an activated process")
+	}
+
+	app := NewApplication(WithTerminator(func(err error) {}))
+	activated := app.Listeners()
+	require.Len(t, activated, 1)
+
+	_, err := app.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	listener, err := app.Listen("tcp", addr)
+	require.NoError(t, err)
+	require.Same(t, activated[0], listener)
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = io.WriteString(conn, "activated")
+	require.NoError(t, err)
+}
@@ -0,0 +1,4 @@
+package lifecycle
+
+// activateListeners is a no-op on windows, which does not support systemd socket activation.
+func (app *Application) activateListeners() {}
@@ -2,35 +2,15 @@ package lifecycle
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"sync"
 	"sync/atomic"
 	"syscall"
+	"time"
 )
 
-// State defines a series of states that the given system may be in.
-type State = int32
-
-const (
-	// StateInvalid marks when a system falls into an invalid state (unused).
-	StateInvalid State = iota
-	// StateInitial marks when a system is in the initial state and can accept plugins for initialization.
-	StateInitial
-	// StateRunning indicates the Run method has been invoked and the application is currently executing.
-	StateRunning
-	// StateStarted indicates the Start method has been invoked and the application is continuously executing.
-	StateStarted
-	// StateShutdown indicates the application is going into a terminated state, either due to an error or signal.
-	StateShutdown
-	// StateTerminated indicates the application is no longer running and typically set prior to exit.
-	StateTerminated
-)
-
-// Hook is used to log semi-fatal errors encountered during state transitions.
-type Hook func(phase string, err error)
-
 // Application provides a pluggable container that manages a systems lifecycle. It ensures that plugins are initialized,
 // started, and shutdown properly. Should an error occur during initialization or startup, any previous plugin needs to
 // be shutdown to ensure it's cleaned up properly. To do this, the Application manages a simple state-machine.
@@ -39,147 +19,472 @@ type Application struct {
 	term func(err error)
 
 	// components for managing state machine
-	state  int32
-	signal chan os.Signal
-	done   chan struct{}
+	created       time.Time
+	state         State
+	signals       []os.Signal
+	reloadSignals []os.Signal
+	signal        chan os.Signal
+	force         <-chan os.Signal
+	custom        chan os.Signal
+	stop          chan struct{}
+	stopping      sync.Once
+	done          chan struct{}
+	ready         chan struct{}
+	terminating   chan struct{}
+	terminated    chan struct{}
+	finalize      sync.Once
+	restarting    sync.Mutex
+	upgrading     sync.Mutex
+	initialized   int32
 
 	// configurable elements of the application
-	context context.Context
-	cancel  context.CancelFunc
+	parent   context.Context
+	context  context.Context
+	cancel   context.CancelFunc
+	logger   *slog.Logger
+	metadata Metadata
+
+	// mu guards the mutable elements of the application which may be accessed from multiple go-routines
+	mu           sync.RWMutex
+	hooks        []owned[Hook]
+	interceptors []owned[Interceptor]
+	listeners    []owned[StateListener]
+	handlers     map[os.Signal][]owned[SignalHandler]
+	plugins      []*registration
+	deferred     []owned[func() error]
+	beforeStart  []func(ctx context.Context) error
+	afterStart   []func(ctx context.Context) error
+	reporters    []ProgressReporter
+	timings      []Timing
+	inflight     map[*registration]invocation
+	sockets      []*managedListener
+	halted       bool
+
+	// current is the plugin being initialized and providers tracks which plugin provided each context value
+	current   *registration
+	providers map[interface{}]string
+
+	// shutdownHooks counts the functions registered using OnShutdown, which are named using their index
+	shutdownHooks int
+
+	// routines tracks the go-routines managed by the application
+	routines sync.WaitGroup
+	halt     chan struct{}
+
+	// work tracks the units of work in-flight (see Track)
+	work work
+
+	initializeTimeout time.Duration
+	startTimeout      time.Duration
+	shutdownTimeout   time.Duration
+	preShutdownDelay  time.Duration
+	forceExitCode     int
+	parallelism       int
+	shutdownWatchdog  Watchdog
+	startupThreshold  time.Duration
+	upgradeTimeout    time.Duration
+	workTimeout       time.Duration
+	timingSummary     bool
+	historySize       int
+	history           *history
+	phases            []customPhase
+
+	// err is the error that caused the application to terminate
+	err          error
+	shutdownErrs []error
+	reason       ShutdownReason
+}
 
-	hook    Hook
-	plugins []Plugin
+// NewApplication constructs an Application configured using the provided options. Options are applied before the
+// application is initialized, making configuration independent of the order in which methods are invoked. A zero-value
+// Application remains usable and is equivalent to calling NewApplication without any options.
+func NewApplication(opts ...Option) *Application {
+	app := &Application{}
+	for _, opt := range opts {
+		opt(app)
+	}
+
+	app.on.Do(app.init)
+	return app
 }
 
 func (app *Application) init() {
-	app.term = func(err error) {
-		if err != nil {
-			log.Fatal(err)
-		}
+	if app.term == nil {
+		app.term = app.exitProcess
+	}
+
+	if app.parent == nil {
+		app.parent = context.Background()
 	}
 
-	app.context, app.cancel = context.WithCancel(context.Background())
-	app.hook = func(phase string, err error) {}
+	app.metadata = defaultMetadata(app.metadata)
+	app.context, app.cancel = context.WithCancel(context.WithValue(app.parent, metadataKey{}, app.metadata))
 
-	atomic.StoreInt32(&app.state, StateInitial)
+	if app.historySize == 0 {
+		app.historySize = defaultHistorySize
+	}
+
+	app.history = newHistory(app.historySize)
+
+	app.created = time.Now()
+	app.setState(StateInitial)
 	app.signal = make(chan os.Signal, 1)
+	app.stop = make(chan struct{})
 	app.done = make(chan struct{}, 1)
+	app.ready = make(chan struct{})
+	app.terminating = make(chan struct{})
+	app.terminated = make(chan struct{})
+	app.halt = make(chan struct{})
+
+	// on windows, os.Interrupt is delivered for CTRL_C and CTRL_BREAK while syscall.SIGTERM is delivered for
+	// CTRL_CLOSE, CTRL_LOGOFF, and CTRL_SHUTDOWN events
+	if app.signals == nil {
+		app.signals = []os.Signal{syscall.SIGTERM, os.Interrupt}
+	}
 
-	signal.Notify(app.signal, syscall.SIGTERM, syscall.SIGINT)
+	// signal.Notify relays all incoming signals when none are provided
+	if len(app.signals) > 0 {
+		signal.Notify(app.signal, app.signals...)
+	}
 
-	go func() {
-		<-app.signal
-		signal.Stop(app.signal)
+	go app.watch()
 
-		atomic.StoreInt32(&app.state, StateShutdown)
+	app.custom = make(chan os.Signal, 1)
+	app.handlers = make(map[os.Signal][]owned[SignalHandler])
+	app.providers = make(map[interface{}]string)
+	app.inflight = make(map[*registration]invocation)
 
-		for i := len(app.plugins); i > 0; i-- {
-			err := app.plugins[i-1].Shutdown(app)
-			if err != nil {
-				app.hook("shutdown", err)
-			}
-		}
+	for _, sig := range app.reloadSignals {
+		app.handleSignal(sig, reload)
+	}
+
+	go app.watchSignals()
 
-		app.cancel()
-		close(app.done)
-	}()
+	if !app.inheritListeners() {
+		app.activateListeners()
+	}
 }
 
 // use a context to share plugins
 
 // WithHook configures a listener that's used to log semi-fatal errors encountered during state transitions. This is
-// typically called from your logger plugin during its initialization phase.
+// typically called from your logger plugin during its initialization phase. WithHook replaces any previously configured
+// hooks. Use AddHook to register additional hooks.
 func (app *Application) WithHook(hook Hook) {
 	app.on.Do(app.init)
-	app.hook = hook
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.hooks = []owned[Hook]{{value: hook, by: app.owner()}}
+}
+
+// AddHook registers an additional listener that's used to log semi-fatal errors encountered during state transitions.
+// This allows metrics, logging, and error-reporting hooks to coexist, each receiving every phase error.
+func (app *Application) AddHook(hook Hook) {
+	app.on.Do(app.init)
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.hooks = append(app.hooks, owned[Hook]{value: hook, by: app.owner()})
 }
 
 // WithValue sets the key on the underlying application context to the provided value. This is used by plugins to pass
-// objects back through to developers.
+// objects back through to developers. Should a plugin set a key previously set by another plugin, the later value
+// shadows the earlier one and a KeyCollisionError is reported to the configured hooks as an EventWarning.
 func (app *Application) WithValue(key, value interface{}) {
 	app.on.Do(app.init)
+
+	app.mu.Lock()
 	app.context = context.WithValue(app.context, key, value)
+
+	provider := app.describeCurrent()
+	previous, collided := app.providers[key]
+	app.providers[key] = provider
+
+	phase := Phase("")
+	if app.current != nil {
+		phase = PhaseInitialize
+	}
+	app.mu.Unlock()
+
+	if collided && previous != provider {
+		app.report(newEvent(EventWarning, phase, provider, time.Now(), &KeyCollisionError{
+			Key:      key,
+			Previous: previous,
+			Current:  provider,
+		}))
+	}
 }
 
 // Context returns the underlying context used by the application so that it make be shared with other systems. This
 // intentionally protects users from accidentally overwriting it.
 func (app *Application) Context() context.Context {
 	app.on.Do(app.init)
+
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+
 	return app.context
 }
 
 var _ Contextual = &Application{}
 
 // Initialize appends the provided list of plugins to the application and initializes each one. This method must be
-// called before calling Run or Start.
+// called before calling Run or Start, even when there are no plugins to initialize. Plugins implementing Validator are
+// validated before any of the provided plugins are initialized. Should a plugin fail to validate or initialize, the
+// application is shutdown and the error is returned by the subsequent call to Run or Start.
 func (app *Application) Initialize(plugins ...Plugin) {
 	app.on.Do(app.init)
 
-	if atomic.LoadInt32(&app.state) > StateInitial {
+	if app.State() > StateInitial {
 		app.shutdown(ErrInitializeAfterStartup)
+		return
 	}
 
-	app.plugins = append(app.plugins, plugins...)
-	for _, plugin := range plugins {
-		err := plugin.Initialize(app)
-		if err != nil {
-			app.hook("initialization", err)
-			app.shutdown(err)
-			return
-		}
+	atomic.StoreInt32(&app.initialized, 1)
+
+	registrations, err := app.register(prioritize(plugins))
+	if err != nil {
+		app.shutdown(err)
+		return
+	}
+
+	if err := app.validate(registrations); err != nil {
+		app.shutdown(err)
+		return
+	}
+
+	started := app.enter(PhaseInitialize)
+
+	err = app.initializeRegistrations(registrations)
+	if err != nil {
+		app.shutdown(err)
+		return
+	}
+
+	app.exit(PhaseInitialize, started, nil)
+}
+
+// initializeRegistrations initializes each of the provided registrations, ordered by their dependencies.
+func (app *Application) initializeRegistrations(registrations []*registration) error {
+	progress := app.trackProgress(PhaseInitialize, registrations)
+
+	return schedule(registrations, app.parallelism, dependenciesOf, func(reg *registration) error {
+		progress.advance(reg)
+		return app.initializeRegistration(reg)
+	})
+}
+
+// initializeRegistration initializes the registered plugin, recording whether it succeeded. Disabled plugins are
+// skipped.
+func (app *Application) initializeRegistration(reg *registration) error {
+	// the plugin providing context values can only be determined when plugins are initialized one at a time
+	if app.parallelism <= 1 {
+		previous := app.setCurrent(reg)
+		defer app.setCurrent(previous)
+	}
+
+	if !enabled(app, reg.plugin) {
+		reg.setStatus(statusDisabled)
+		return nil
+	}
+
+	err := app.invoke(app.Context(), PhaseInitialize, app.initializeTimeout, reg, initializePlugin)
+	if err != nil {
+		reg.setStatus(statusFailed)
+		return err
 	}
+
+	reg.setStatus(statusInitialized)
+	return nil
 }
 
-// Run executes each plugins Run method. There is often only one of these, but some plugins (like a logger) might
-// implement Run to log state transitions. Once this method is called, you will be unable to Initialize any more
-// plugins. You will also be unable to call the Start method.
+// Run executes each plugins Run method and terminates the application using the error returned by RunE.
 func (app *Application) Run() {
+	app.term(app.RunE())
+}
+
+// RunE executes each plugins Run method. There is often only one of these, but some plugins (like a logger) might
+// implement Run to log state transitions. Once this method is called, you will be unable to Initialize any more
+// plugins. You will also be unable to call the Start method. Once all plugins have been shutdown, the error that caused
+// the application to terminate is returned (nil if the application completed successfully).
+func (app *Application) RunE() error {
 	app.on.Do(app.init)
 
-	if !atomic.CompareAndSwapInt32(&app.state, StateInitial, StateRunning) {
-		app.shutdown(ErrRunOrStart)
+	if app.State() == StateTerminated {
+		return app.Err()
+	}
+
+	if !app.transition(StateInitial, StateRunning) {
+		return app.shutdown(ErrRunOrStart)
+	}
+
+	if atomic.LoadInt32(&app.initialized) == 0 {
+		return app.shutdown(ErrNotInitialized)
+	}
+
+	if err := app.runStartupPhases(PhaseRun); err != nil {
+		return app.shutdown(err)
 	}
 
-	for _, plugin := range app.plugins {
-		err := plugin.Run(app)
+	app.logTimings()
+
+	started := app.enter(PhaseRun)
+
+	plugins := initializedOnly(app.registered())
+	progress := app.trackProgress(PhaseRun, plugins)
+
+	for _, reg := range plugins {
+		if app.isTerminating() {
+			break // shutdown was triggered elsewhere
+		}
+
+		progress.advance(reg)
+
+		err := app.invoke(app.Context(), PhaseRun, 0, reg, runPlugin)
 		if err != nil {
-			app.hook("running", err)
-			app.shutdown(err)
-			return
+			return app.shutdown(err)
 		}
 	}
 
-	app.shutdown(nil)
+	app.exit(PhaseRun, started, nil)
+
+	return app.shutdown(app.runPhases(After, PhaseRun))
 }
 
-// Start executes each plugins Start method. This is often used to start long running servers, begin stat emissions,
-// or initialize control loops. Once this method is called, you will be unable to Initialize any more plugins. You will
-// also be unable to call the Start method.
+// runStartupPhases runs the custom phases positioned after PhaseInitialize and before the provided phase.
+func (app *Application) runStartupPhases(phase Phase) error {
+	if err := app.runPhases(After, PhaseInitialize); err != nil {
+		return err
+	}
+	return app.runPhases(Before, phase)
+}
+
+// RunContext behaves like RunE, but ties the lifetime of the application to the provided context. Should the context be
+// canceled (or its deadline be exceeded) before the plugins complete, the application is shutdown.
+func (app *Application) RunContext(ctx context.Context) error {
+	app.on.Do(app.init)
+
+	go app.watchContext(ctx)
+	return app.RunE()
+}
+
+// Start executes each plugins Start method and terminates the application using the error returned by StartE.
 func (app *Application) Start() {
+	app.term(app.StartE())
+}
+
+// StartE executes each plugins Start method. This is often used to start long running servers, begin stat emissions, or
+// initialize control loops. Once this method is called, you will be unable to Initialize any more plugins. You will
+// also be unable to call the Run method. When configured using WithParallelism, independent plugins are started
+// concurrently and the context provided to plugins still starting is canceled should another fail. Plugins implementing
+// Readiness must report they are ready before the start phase completes (see Ready). StartE blocks until the
+// application has been shutdown and returns the error that caused the application to terminate (nil if the application
+// was shutdown cleanly).
+func (app *Application) StartE() error {
 	app.on.Do(app.init)
 
-	if !atomic.CompareAndSwapInt32(&app.state, StateInitial, StateStarted) {
-		app.shutdown(ErrRunOrStart)
+	if app.State() == StateTerminated {
+		return app.Err()
+	}
+
+	if !app.transition(StateInitial, StateStarted) {
+		return app.shutdown(ErrRunOrStart)
+	}
+
+	if atomic.LoadInt32(&app.initialized) == 0 {
+		return app.shutdown(ErrNotInitialized)
+	}
+
+	if err := app.runStartupPhases(PhaseStart); err != nil {
+		return app.shutdown(err)
 	}
 
-	for _, plugin := range app.plugins {
-		err := plugin.Start(app)
+	if err := app.runCallbacks(&app.beforeStart); err != nil {
+		return app.shutdown(err)
+	}
+
+	err := app.startPlugins()
+	if err == nil {
+		err = app.runCallbacks(&app.afterStart)
+	}
+
+	if err == nil {
+		err = app.runPhases(After, PhaseStart)
+	}
+
+	if err == nil {
+		close(app.readied())
+		app.logTimings()
+	}
+
+	if err != nil && err != errInterrupted {
+		return app.shutdown(err)
+	}
+
+	<-app.done
+	return app.terminate()
+}
+
+// startPlugins starts each initialized plugin and waits for them to be ready, returning errInterrupted should shutdown
+// be triggered elsewhere.
+func (app *Application) startPlugins() error {
+	started := app.enter(PhaseStart)
+
+	// contexts provided to plugins that are starting are canceled should another plugin fail to start
+	starting := &cancelGroup{}
+	defer starting.cancel()
+
+	plugins := initializedOnly(app.registered())
+	progress := app.trackProgress(PhaseStart, plugins)
+
+	err := schedule(plugins, app.parallelism, dependenciesOf, func(reg *registration) error {
+		if app.isTerminating() {
+			return errInterrupted // shutdown was triggered elsewhere
+		}
+
+		progress.advance(reg)
+
+		err := app.invoke(starting.with(app.Context()), PhaseStart, app.startTimeout, reg, startPlugin)
 		if err != nil {
-			app.hook("startup", err)
-			app.shutdown(err)
-			return
+			starting.cancel()
+			return err
 		}
+
+		reg.setStatus(statusStarted)
+		app.supervise(reg)
+		return nil
+	})
+
+	if err == nil {
+		err = app.awaitReadiness()
 	}
 
-	<-app.done
+	if err == nil {
+		app.exit(PhaseStart, started, nil)
+	}
+
+	return err
 }
 
-func (app *Application) shutdown(err error) {
-	app.signal <- os.Interrupt
-	<-app.done
+// StartContext behaves like StartE, but ties the lifetime of the application to the provided context. Once the context
+// is canceled (or its deadline is exceeded), the application is shutdown.
+func (app *Application) StartContext(ctx context.Context) error {
+	app.on.Do(app.init)
 
-	atomic.StoreInt32(&app.state, StateTerminated)
-	app.hook("terminated", err)
+	go app.watchContext(ctx)
+	return app.StartE()
+}
 
-	app.term(err)
+// watchContext shuts down the application once the provided context is done. Similar to the context provided using
+// WithContext, cancellation is not treated as an error.
+func (app *Application) watchContext(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		app.setReason(newShutdownReason(ShutdownCanceled, ctx.Err()))
+		app.Shutdown(nil)
+	case <-app.done:
+	}
 }
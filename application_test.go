@@ -1,8 +1,17 @@
 package lifecycle
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -38,10 +47,7 @@ func countingPlugin() (map[string]int, *PluginFuncs) {
 }
 
 func newTestApp(term func(err error)) *Application {
-	app := &Application{}
-	app.on.Do(app.init) // force initialization
-	app.term = term
-	return app
+	return NewApplication(WithTerminator(term))
 }
 
 func Test_ApplicationInitialize_Error(t *testing.T) {
@@ -85,7 +91,7 @@ func Test_ApplicationRun(t *testing.T) {
 func Test_ApplicationRun_Error(t *testing.T) {
 	app := newTestApp(func(err error) {
 		require.Error(t, err, "application did not fail with error")
-		require.Equal(t, "something went wrong", err.Error())
+		require.Equal(t, "plugin[1] (*lifecycle.PluginFuncs) failed to run: something went wrong", err.Error())
 	})
 
 	counts, executionCountPlugin := countingPlugin()
@@ -149,7 +155,7 @@ func Test_ApplicationStart(t *testing.T) {
 func Test_ApplicationStart_Error(t *testing.T) {
 	app := newTestApp(func(err error) {
 		require.Error(t, err, "application did not fail with error")
-		require.Equal(t, "something went wrong", err.Error())
+		require.Equal(t, "plugin[2] (*lifecycle.PluginFuncs) failed to start: something went wrong", err.Error())
 	})
 
 	counts, executionCountPlugin := countingPlugin()
@@ -183,3 +189,2567 @@ func Test_ApplicationStart_Error(t *testing.T) {
 	require.Equal(t, 0, counts[run], "unexpected run count")
 	require.Equal(t, 1, counts[shutdown], "unexpected shutdown count")
 }
+
+type contextPlugin struct {
+	PluginFuncs
+	contexts map[string]context.Context
+	errs     map[string]error
+}
+
+func newContextPlugin() *contextPlugin {
+	return &contextPlugin{
+		contexts: make(map[string]context.Context),
+		errs:     make(map[string]error),
+	}
+}
+
+func (p *contextPlugin) InitializeContext(ctx context.Context, app *Application) error {
+	p.contexts[initialize] = ctx
+	p.errs[initialize] = ctx.Err()
+	return nil
+}
+
+func (p *contextPlugin) RunContext(ctx context.Context, app *Application) error {
+	p.contexts[run] = ctx
+	p.errs[run] = ctx.Err()
+	return nil
+}
+
+func (p *contextPlugin) StartContext(ctx context.Context, app *Application) error {
+	p.contexts[start] = ctx
+	p.errs[start] = ctx.Err()
+	return nil
+}
+
+func (p *contextPlugin) ShutdownContext(ctx context.Context, app *Application) error {
+	p.contexts[shutdown] = ctx
+	p.errs[shutdown] = ctx.Err()
+	return nil
+}
+
+func Test_ApplicationRun_PluginContext(t *testing.T) {
+	app := newTestApp(func(err error) {
+		require.NoError(t, err, "application unexpectedly failed with error")
+	})
+
+	plugin := newContextPlugin()
+	plugin.InitializeFunc = func(app *Application) error {
+		return fmt.Errorf("plain initialize should not be called")
+	}
+
+	app.Initialize(plugin)
+	app.Run()
+
+	require.NotNil(t, plugin.contexts[initialize], "initialize context not provided")
+	require.NotNil(t, plugin.contexts[run], "run context not provided")
+	require.Nil(t, plugin.contexts[start], "unexpected start context")
+	require.NotNil(t, plugin.contexts[shutdown], "shutdown context not provided")
+}
+
+func Test_NewApplication(t *testing.T) {
+	phases := make([]Phase, 0)
+
+	app := NewApplication(
+		WithHook(func(event Event) {
+			phases = append(phases, event.Phase)
+		}),
+		WithTerminator(func(err error) {
+			require.NoError(t, err, "application unexpectedly failed with error")
+		}),
+	)
+
+	counts, executionCountPlugin := countingPlugin()
+
+	app.Initialize(executionCountPlugin)
+	app.Run()
+
+	require.Equal(t, 1, counts[initialize], "unexpected initialize count")
+	require.Equal(t, 1, counts[run], "unexpected run count")
+	require.Equal(t, 1, counts[shutdown], "unexpected shutdown count")
+	require.Equal(t, []Phase{
+		PhaseInitialize, PhaseInitialize, PhaseInitialize,
+		PhaseRun, PhaseRun, PhaseRun,
+		PhaseShutdown, PhaseShutdown, PhaseShutdown,
+		PhaseTerminated,
+	}, phases, "unexpected hook phases")
+}
+
+func Test_ApplicationRunE_Error(t *testing.T) {
+	app := newTestApp(func(err error) {
+		require.Fail(t, "terminator unexpectedly invoked")
+	})
+
+	counts, executionCountPlugin := countingPlugin()
+
+	app.Initialize(
+		executionCountPlugin,
+		&PluginFuncs{
+			RunFunc: func(app *Application) error {
+				return fmt.Errorf("something went wrong")
+			},
+		},
+	)
+
+	err := app.RunE()
+	require.Error(t, err, "application did not fail with error")
+	require.Equal(t, "plugin[1] (*lifecycle.PluginFuncs) failed to run: something went wrong", err.Error())
+
+	require.Equal(t, 1, counts[initialize], "unexpected initialize count")
+	require.Equal(t, 1, counts[run], "unexpected run count")
+	require.Equal(t, 1, counts[shutdown], "unexpected shutdown count")
+}
+
+func Test_ApplicationStartE_InitializeError(t *testing.T) {
+	app := newTestApp(func(err error) {
+		require.Fail(t, "terminator unexpectedly invoked")
+	})
+
+	counts, executionCountPlugin := countingPlugin()
+
+	app.Initialize(
+		executionCountPlugin,
+		&PluginFuncs{
+			InitializeFunc: func(app *Application) error {
+				return fmt.Errorf("something went wrong")
+			},
+		},
+	)
+
+	err := app.StartE()
+	require.Error(t, err, "application did not fail with error")
+	require.Equal(t, "plugin[1] (*lifecycle.PluginFuncs) failed to initialize: something went wrong", err.Error())
+
+	require.Equal(t, 1, counts[initialize], "unexpected initialize count")
+	require.Equal(t, 0, counts[start], "unexpected start count")
+	require.Equal(t, 1, counts[shutdown], "unexpected shutdown count")
+}
+
+func Test_ApplicationShutdown(t *testing.T) {
+	app := newTestApp(func(err error) {
+		require.Fail(t, "terminator unexpectedly invoked")
+	})
+
+	counts, executionCountPlugin := countingPlugin()
+
+	app.Initialize(
+		executionCountPlugin,
+		&PluginFuncs{
+			StartFunc: func(app *Application) error {
+				go app.Shutdown(fmt.Errorf("unrecoverable condition"))
+				return nil
+			},
+		},
+	)
+
+	err := app.StartE()
+	require.Error(t, err, "application did not fail with error")
+	require.Equal(t, "unrecoverable condition", err.Error())
+	require.Equal(t, ShutdownRequested, app.ShutdownReason().Kind)
+
+	require.Equal(t, 1, counts[initialize], "unexpected initialize count")
+	require.Equal(t, 1, counts[start], "unexpected start count")
+	require.Equal(t, 1, counts[shutdown], "unexpected shutdown count")
+}
+
+func Test_ApplicationWithoutSignalHandling(t *testing.T) {
+	app := NewApplication(WithoutSignalHandling())
+	require.Empty(t, app.signals, "unexpected signals")
+
+	// ensure signal handling remains disabled
+	app.Initialize()
+	require.Empty(t, app.signals, "unexpected signals")
+}
+
+func Test_ApplicationWithShutdownTimeout(t *testing.T) {
+	app := NewApplication(
+		WithShutdownTimeout(10*time.Millisecond),
+		WithTerminator(func(err error) {
+			require.Fail(t, "terminator unexpectedly invoked")
+		}),
+	)
+
+	blocked := make(chan struct{})
+	defer close(blocked)
+
+	app.Initialize(
+		&PluginFuncs{
+			ShutdownFunc: func(app *Application) error {
+				<-blocked
+				return nil
+			},
+		},
+	)
+
+	err := app.RunE()
+	require.Error(t, err, "application did not fail with error")
+
+	timeoutErr := &TimeoutError{}
+	require.True(t, errors.As(err, &timeoutErr), "unexpected error type")
+	require.Equal(t, []string{"plugin[0] (*lifecycle.PluginFuncs)"}, timeoutErr.Plugins)
+	require.ErrorIs(t, err, ErrShutdownTimeout)
+}
+
+func Test_ApplicationWithShutdownBudget(t *testing.T) {
+	var shutdownErr error
+
+	app := NewApplication(
+		WithHook(func(event Event) {
+			if event.Phase == PhaseShutdown && event.Err != nil {
+				shutdownErr = event.Err
+			}
+		}),
+		WithTerminator(func(err error) {
+			require.ErrorIs(t, err, shutdownErr, "shutdown error not delivered to terminator")
+		}),
+	)
+
+	counts, executionCountPlugin := countingPlugin()
+
+	blocked := make(chan struct{})
+	defer close(blocked)
+
+	app.Initialize(
+		executionCountPlugin,
+		WithShutdownBudget(10*time.Millisecond, &PluginFuncs{
+			ShutdownFunc: func(app *Application) error {
+				<-blocked
+				return nil
+			},
+		}),
+	)
+
+	app.Run()
+
+	require.Equal(t, 1, counts[shutdown], "unexpected shutdown count")
+
+	timeoutErr := &TimeoutError{}
+	require.True(t, errors.As(shutdownErr, &timeoutErr), "unexpected error type")
+	require.Equal(t, []string{"plugin[1] (*lifecycle.PluginFuncs)"}, timeoutErr.Plugins)
+}
+
+func Test_ApplicationWithInitializeTimeout(t *testing.T) {
+	app := NewApplication(
+		WithInitializeTimeout(10*time.Millisecond),
+		WithTerminator(func(err error) {
+			require.Fail(t, "terminator unexpectedly invoked")
+		}),
+	)
+
+	counts, executionCountPlugin := countingPlugin()
+
+	blocked := make(chan struct{})
+	defer close(blocked)
+
+	app.Initialize(
+		executionCountPlugin,
+		&PluginFuncs{
+			InitializeFunc: func(app *Application) error {
+				<-blocked
+				return nil
+			},
+		},
+	)
+
+	err := app.StartE()
+	require.Error(t, err, "application did not fail with error")
+
+	timeoutErr := &TimeoutError{}
+	require.True(t, errors.As(err, &timeoutErr), "unexpected error type")
+	require.Equal(t, PhaseInitialize, timeoutErr.Phase)
+	require.Equal(t, []string{"plugin[1] (*lifecycle.PluginFuncs)"}, timeoutErr.Plugins)
+	require.ErrorIs(t, err, ErrInitializeTimeout)
+	require.False(t, errors.Is(err, ErrShutdownTimeout), "unexpected shutdown timeout")
+
+	require.Equal(t, 0, counts[start], "unexpected start count")
+	require.Equal(t, 1, counts[shutdown], "unexpected shutdown count")
+}
+
+func Test_ExitCode(t *testing.T) {
+	require.Equal(t, 0, ExitCode(nil))
+	require.Equal(t, 1, ExitCode(fmt.Errorf("something went wrong")))
+	require.Equal(t, 2, ExitCode(NewExitError(2, fmt.Errorf("invalid configuration"))))
+	require.Equal(t, 3, ExitCode(fmt.Errorf("wrapped: %w", NewExitError(3, fmt.Errorf("dependency failure")))))
+}
+
+func Test_ApplicationPluginError(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	connErr := fmt.Errorf("connection refused")
+
+	app.Initialize(
+		&PluginFuncs{},
+		&PluginFuncs{
+			StartFunc: func(app *Application) error {
+				return connErr
+			},
+		},
+	)
+
+	err := app.StartE()
+
+	pluginErr := &PluginError{}
+	require.True(t, errors.As(err, &pluginErr), "unexpected error type")
+	require.Equal(t, "plugin[1] (*lifecycle.PluginFuncs)", pluginErr.Plugin)
+	require.Equal(t, PhaseStart, pluginErr.Phase)
+	require.ErrorIs(t, err, connErr)
+
+	reason := app.ShutdownReason()
+	require.Equal(t, ShutdownFailed, reason.Kind)
+	require.Equal(t, "plugin[1] (*lifecycle.PluginFuncs)", reason.Plugin)
+	require.Equal(t, PhaseStart, reason.Phase)
+}
+
+func Test_ApplicationShutdownReason(t *testing.T) {
+	app := newTestApp(func(err error) {})
+	require.Equal(t, ShutdownNone, app.ShutdownReason().Kind)
+
+	app.Initialize(&PluginFuncs{})
+	require.NoError(t, app.RunE())
+
+	reason := app.ShutdownReason()
+	require.Equal(t, ShutdownCompleted, reason.Kind)
+	require.Equal(t, "completed", reason.String())
+}
+
+func Test_ApplicationRunE_NotInitialized(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	require.ErrorIs(t, app.RunE(), ErrNotInitialized)
+	require.Equal(t, StateTerminated, app.State())
+}
+
+func Test_ApplicationReload_AlreadyTerminated(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	app.Initialize(&PluginFuncs{})
+	require.NoError(t, app.RunE())
+
+	require.ErrorIs(t, app.Reload(), ErrAlreadyTerminated)
+}
+
+func Test_ApplicationWait(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	app.Initialize(
+		&PluginFuncs{
+			StartFunc: func(app *Application) error {
+				return fmt.Errorf("something went wrong")
+			},
+		},
+	)
+
+	go app.Start()
+
+	err := app.Wait()
+	require.Error(t, err, "application did not fail with error")
+	require.Equal(t, "plugin[0] (*lifecycle.PluginFuncs) failed to start: something went wrong", err.Error())
+	require.Equal(t, StateTerminated, app.State())
+}
+
+func Test_ApplicationWait_ShutdownBeforeRun(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	counts, executionCountPlugin := countingPlugin()
+	app.Initialize(executionCountPlugin)
+	app.Shutdown(fmt.Errorf("out of memory"))
+
+	waited := make(chan error, 1)
+	go func() {
+		waited <- app.Wait()
+	}()
+
+	select {
+	case err := <-waited:
+		require.EqualError(t, err, "out of memory")
+	case <-time.After(time.Second):
+		require.Fail(t, "wait blocked when shutdown before run or start")
+	}
+
+	require.Equal(t, StateTerminated, app.State())
+	require.Equal(t, map[string]int{initialize: 1, shutdown: 1}, counts)
+	require.EqualError(t, app.RunE(), "out of memory")
+}
+
+func Test_ApplicationDone(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	counts, executionCountPlugin := countingPlugin()
+	app.Initialize(executionCountPlugin)
+
+	select {
+	case <-app.Done():
+		require.Fail(t, "done closed prior to shutdown")
+	default:
+	}
+
+	app.Shutdown(nil)
+	<-app.Done()
+
+	require.Equal(t, 1, counts[shutdown], "unexpected shutdown count")
+}
+
+func Test_StateString(t *testing.T) {
+	require.Equal(t, "initial", StateInitial.String())
+	require.Equal(t, "started", StateStarted.String())
+	require.Equal(t, "terminated", StateTerminated.String())
+	require.Equal(t, "invalid", State(42).String())
+}
+
+func Test_ApplicationErr(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	app.Initialize(
+		&PluginFuncs{
+			RunFunc: func(app *Application) error {
+				return fmt.Errorf("something went wrong")
+			},
+		},
+	)
+
+	require.NoError(t, app.Err(), "unexpected error prior to termination")
+
+	go app.Run()
+	require.Error(t, app.Wait(), "application did not fail with error")
+	require.Equal(t, "plugin[0] (*lifecycle.PluginFuncs) failed to run: something went wrong", app.Err().Error())
+}
+
+func Test_ApplicationAddHook(t *testing.T) {
+	first, second := make([]Phase, 0), make([]Phase, 0)
+
+	app := newTestApp(func(err error) {})
+	app.WithHook(func(event Event) {
+		if event.Err != nil {
+			first = append(first, event.Phase)
+		}
+	})
+	app.AddHook(func(event Event) {
+		if event.Err != nil {
+			second = append(second, event.Phase)
+		}
+	})
+
+	app.Initialize(
+		&PluginFuncs{
+			RunFunc: func(app *Application) error {
+				return fmt.Errorf("something went wrong")
+			},
+		},
+	)
+
+	_ = app.RunE()
+
+	require.Equal(t, []Phase{PhaseRun, PhaseTerminated}, first, "unexpected hook phases")
+	require.Equal(t, []Phase{PhaseRun, PhaseTerminated}, second, "unexpected hook phases")
+}
+
+func Test_ApplicationHook_Event(t *testing.T) {
+	events := make([]Event, 0)
+
+	app := NewApplication(
+		WithHook(func(event Event) {
+			if event.Err != nil {
+				events = append(events, event)
+			}
+		}),
+		WithTerminator(func(err error) {}),
+	)
+
+	app.Initialize(
+		&PluginFuncs{
+			RunFunc: func(app *Application) error {
+				time.Sleep(time.Millisecond)
+				return fmt.Errorf("something went wrong")
+			},
+		},
+	)
+
+	_ = app.RunE()
+
+	require.Len(t, events, 2, "unexpected number of events")
+	require.Equal(t, PhaseRun, events[0].Phase)
+	require.Equal(t, "plugin[0] (*lifecycle.PluginFuncs)", events[0].Plugin)
+	require.Equal(t, "plugin[0] (*lifecycle.PluginFuncs) failed to run: something went wrong", events[0].Err.Error())
+	require.GreaterOrEqual(t, events[0].Duration, time.Millisecond)
+	require.Equal(t, PhaseTerminated, events[1].Phase)
+	require.Empty(t, events[1].Plugin)
+}
+
+func Test_ApplicationHook_Transitions(t *testing.T) {
+	events := make([]Event, 0)
+
+	app := NewApplication(
+		WithHook(func(event Event) {
+			events = append(events, event)
+		}),
+		WithTerminator(func(err error) {
+			require.NoError(t, err, "application unexpectedly failed with error")
+		}),
+	)
+
+	app.Initialize(&PluginFuncs{})
+	app.Run()
+
+	kinds := make([]EventKind, len(events))
+	for i, event := range events {
+		kinds[i] = event.Kind
+		require.NoError(t, event.Err, "unexpected event error")
+	}
+
+	require.Equal(t, []EventKind{
+		EventPhaseEnter, EventPlugin, EventPhaseExit,
+		EventPhaseEnter, EventPlugin, EventPhaseExit,
+		EventPhaseEnter, EventPlugin, EventPhaseExit,
+		EventPhaseEnter,
+	}, kinds, "unexpected event kinds")
+
+	require.Equal(t, "plugin[0] (*lifecycle.PluginFuncs)", events[4].Plugin)
+	require.Equal(t, PhaseRun, events[4].Phase)
+}
+
+func Test_ApplicationOnStateChange(t *testing.T) {
+	transitions := make([]string, 0)
+
+	app := newTestApp(func(err error) {})
+	app.OnStateChange(func(from, to State) {
+		transitions = append(transitions, from.String()+" -> "+to.String())
+	})
+
+	app.Initialize(&PluginFuncs{})
+	app.Run()
+
+	require.Equal(t, []string{
+		"initial -> running",
+		"running -> shutdown",
+		"shutdown -> terminated",
+	}, transitions, "unexpected transitions")
+}
+
+func Test_ApplicationWithContext(t *testing.T) {
+	key := ContextKey("tenant")
+	ctx := context.WithValue(context.Background(), key, "effx")
+
+	app := NewApplication(WithContext(ctx))
+	require.Equal(t, "effx", app.Context().Value(key))
+}
+
+func Test_ApplicationWithContext_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	app := NewApplication(
+		WithContext(ctx),
+		WithTerminator(func(err error) {
+			require.NoError(t, err, "application unexpectedly failed with error")
+		}),
+	)
+
+	plugin := newContextPlugin()
+	app.Initialize(
+		plugin,
+		&PluginFuncs{
+			StartFunc: func(app *Application) error {
+				cancel()
+				return nil
+			},
+		},
+	)
+
+	app.Start()
+
+	require.NotNil(t, plugin.contexts[shutdown], "shutdown context not provided")
+	require.NoError(t, plugin.errs[shutdown], "shutdown context unexpectedly canceled")
+	require.Equal(t, StateTerminated, app.State())
+	require.Equal(t, ShutdownCanceled, app.ShutdownReason().Kind)
+}
+
+func Test_ApplicationStartContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	app := newTestApp(func(err error) {})
+
+	counts, executionCountPlugin := countingPlugin()
+	app.Initialize(executionCountPlugin)
+
+	require.NoError(t, app.StartContext(ctx))
+	require.Equal(t, 1, counts[shutdown], "unexpected shutdown count")
+
+	reason := app.ShutdownReason()
+	require.Equal(t, ShutdownCanceled, reason.Kind)
+	require.ErrorIs(t, reason.Err, context.DeadlineExceeded)
+}
+
+func Test_ApplicationRunContext(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	counts, executionCountPlugin := countingPlugin()
+	app.Initialize(executionCountPlugin)
+
+	require.NoError(t, app.RunContext(context.Background()))
+	require.Equal(t, 1, counts[run], "unexpected run count")
+	require.Equal(t, ShutdownCompleted, app.ShutdownReason().Kind)
+}
+
+func Test_ApplicationGo(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	stopped := int32(0)
+	app.Initialize(
+		&PluginFuncs{
+			StartFunc: func(app *Application) error {
+				app.Go(func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				})
+
+				app.Go(func(ctx context.Context) error {
+					<-ctx.Done()
+					atomic.AddInt32(&stopped, 1)
+					return nil
+				})
+
+				go app.Shutdown(nil)
+				return nil
+			},
+			ShutdownFunc: func(app *Application) error {
+				require.Equal(t, int32(1), atomic.LoadInt32(&stopped), "go-routine not stopped before shutdown")
+				return nil
+			},
+		},
+	)
+
+	require.NoError(t, app.StartE())
+}
+
+func Test_ApplicationGo_Error(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	app.Initialize(
+		&PluginFuncs{
+			StartFunc: func(app *Application) error {
+				app.Go(func(ctx context.Context) error {
+					return fmt.Errorf("lost connection")
+				})
+				return nil
+			},
+		},
+	)
+
+	err := app.StartE()
+	require.EqualError(t, err, "lost connection")
+	require.Equal(t, ShutdownFailed, app.ShutdownReason().Kind)
+}
+
+func Test_ApplicationOnShutdown(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	order := make([]string, 0)
+	app.Initialize(
+		&PluginFuncs{
+			ShutdownFunc: func(app *Application) error {
+				order = append(order, "plugin")
+				return nil
+			},
+		},
+		&PluginFuncs{
+			RunFunc: func(app *Application) error {
+				app.OnShutdown(func(ctx context.Context) error {
+					require.NoError(t, ctx.Err(), "shutdown context unexpectedly canceled")
+					order = append(order, "hook")
+					return fmt.Errorf("failed to remove temp dir")
+				})
+				return nil
+			},
+		},
+	)
+
+	err := app.RunE()
+	require.EqualError(t, err, "shutdown hook[0] (*lifecycle.shutdownHook) failed to shutdown: failed to remove temp dir")
+	require.Equal(t, []string{"hook", "plugin"}, order)
+	require.Equal(t, []string{"plugin[0]", "plugin[1]"}, app.Plugins())
+	require.Len(t, app.PluginStatuses(), 2)
+}
+
+func Test_ApplicationDefer(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	order := make([]string, 0)
+	app.Initialize(
+		&PluginFuncs{
+			InitializeFunc: func(app *Application) error {
+				app.Defer(func() error {
+					order = append(order, "first")
+					return nil
+				})
+				app.Defer(func() error {
+					order = append(order, "second")
+					return fmt.Errorf("failed to release lock")
+				})
+				return nil
+			},
+			ShutdownFunc: func(app *Application) error {
+				order = append(order, "plugin")
+				return nil
+			},
+		},
+	)
+
+	err := app.RunE()
+	require.EqualError(t, err, "failed to release lock")
+	require.Equal(t, []string{"plugin", "second", "first"}, order)
+}
+
+type testCloser struct {
+	name   string
+	closed *[]string
+}
+
+func (c *testCloser) Close() error {
+	*c.closed = append(*c.closed, c.name)
+	return nil
+}
+
+func Test_ApplicationWithCloser(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	closed := make([]string, 0)
+	db := &testCloser{name: "db", closed: &closed}
+
+	app.Initialize(
+		&PluginFuncs{
+			InitializeFunc: func(app *Application) error {
+				app.WithCloser(ContextKey("db"), db)
+				app.WithCloser(ContextKey("cache"), &testCloser{name: "cache", closed: &closed})
+				return nil
+			},
+		},
+	)
+
+	require.Equal(t, db, app.Context().Value(ContextKey("db")))
+	require.NoError(t, app.RunE())
+	require.Equal(t, []string{"cache", "db"}, closed)
+}
+
+func Test_SetGet(t *testing.T) {
+	app := NewApplication()
+
+	_, ok := Get[*testCloser](app)
+	require.False(t, ok, "unexpected value")
+
+	db := &testCloser{name: "db"}
+	Set(app, db)
+	Set(app, "effx")
+
+	value, ok := Get[*testCloser](app)
+	require.True(t, ok, "value not found")
+	require.Equal(t, db, value)
+
+	name, ok := Get[string](app)
+	require.True(t, ok, "value not found")
+	require.Equal(t, "effx", name)
+}
+
+func Test_MustValue(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	app.Initialize(
+		&PluginFuncs{
+			InitializeFunc: func(app *Application) error {
+				app.WithValue(ContextKey("db"), "postgres")
+				return nil
+			},
+		},
+		&PluginFuncs{
+			InitializeFunc: func(app *Application) error {
+				require.Equal(t, "postgres", MustValue[string](app, ContextKey("db")))
+
+				require.PanicsWithValue(t,
+					"lifecycle: value for lifecycle.db provided by plugin[0] (*lifecycle.PluginFuncs) is string, not int",
+					func() { MustValue[int](app, ContextKey("db")) },
+				)
+
+				require.PanicsWithValue(t,
+					"lifecycle: no value provided for type *lifecycle.testCloser "+
+						"(requested by plugin[1] (*lifecycle.PluginFuncs) during initialize, "+
+						"was the plugin providing it initialized first?)",
+					func() { MustGet[*testCloser](app) },
+				)
+				return nil
+			},
+		},
+	)
+
+	require.NoError(t, app.RunE())
+}
+
+func Test_ApplicationWithValue_Collision(t *testing.T) {
+	warnings := make([]Event, 0)
+
+	app := NewApplication(
+		WithHook(func(event Event) {
+			if event.Kind == EventWarning {
+				warnings = append(warnings, event)
+			}
+		}),
+		WithTerminator(func(err error) {}),
+	)
+
+	provide := func(app *Application) error {
+		app.WithValue(ContextKey("db"), "postgres")
+		app.WithValue(ContextKey("db"), "postgres")
+		return nil
+	}
+
+	app.Initialize(&PluginFuncs{InitializeFunc: provide}, &PluginFuncs{InitializeFunc: provide})
+	require.NoError(t, app.RunE())
+	require.Len(t, warnings, 1)
+
+	collisionErr := &KeyCollisionError{}
+	require.True(t, errors.As(warnings[0].Err, &collisionErr), "unexpected error type")
+	require.Equal(t, ContextKey("db"), collisionErr.Key)
+	require.Equal(t, "plugin[0] (*lifecycle.PluginFuncs)", collisionErr.Previous)
+	require.Equal(t, "plugin[1] (*lifecycle.PluginFuncs)", collisionErr.Current)
+	require.Equal(t, PhaseInitialize, warnings[0].Phase)
+}
+
+type namedPlugin struct {
+	PluginFuncs
+	name string
+}
+
+func (p *namedPlugin) Name() string {
+	return p.name
+}
+
+func Test_ApplicationPlugin(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	server := &namedPlugin{
+		PluginFuncs: PluginFuncs{
+			StartFunc: func(app *Application) error {
+				return fmt.Errorf("address already in use")
+			},
+		},
+		name: "http-server",
+	}
+
+	anonymous := &PluginFuncs{}
+	app.Initialize(anonymous, WithShutdownBudget(time.Second, server))
+
+	plugin, ok := app.Plugin("http-server")
+	require.True(t, ok, "plugin not found")
+	require.Equal(t, server, plugin)
+
+	plugin, ok = app.Plugin("plugin[0]")
+	require.True(t, ok, "plugin not found")
+	require.Equal(t, anonymous, plugin)
+
+	_, ok = app.Plugin("grpc-server")
+	require.False(t, ok, "unexpected plugin")
+
+	require.Equal(t, []string{"plugin[0]", "http-server"}, app.Plugins())
+
+	err := app.StartE()
+	require.EqualError(t, err, "http-server (*lifecycle.namedPlugin) failed to start: address already in use")
+}
+
+func Test_ApplicationInitialize_Duplicate(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	counts, executionCountPlugin := countingPlugin()
+	app.Initialize(executionCountPlugin)
+	app.Initialize(&PluginFuncs{}, WithShutdownBudget(time.Second, executionCountPlugin))
+
+	err := app.RunE()
+	require.ErrorIs(t, err, ErrDuplicatePlugin)
+	require.EqualError(t, err, "plugin registered more than once: "+
+		"plugin[2] (*lifecycle.PluginFuncs) conflicts with plugin[0] (*lifecycle.PluginFuncs)")
+	require.Equal(t, 1, counts[initialize], "unexpected initialize count")
+	require.Equal(t, 1, counts[shutdown], "unexpected shutdown count")
+}
+
+func Test_ApplicationInitialize_DuplicateName(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	app.Initialize(&namedPlugin{name: "http-server"}, &namedPlugin{name: "http-server"})
+	require.ErrorIs(t, app.RunE(), ErrDuplicatePlugin)
+}
+
+func Test_ApplicationInitialize_Priority(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	order := make([]string, 0)
+	plugin := func(name string) Plugin {
+		return &namedPlugin{
+			PluginFuncs: PluginFuncs{
+				StartFunc: func(app *Application) error {
+					order = append(order, name)
+					return nil
+				},
+			},
+			name: name,
+		}
+	}
+
+	app.Initialize(
+		plugin("http-server"),
+		plugin("metrics"),
+		WithPriority(10, plugin("logger")),
+		WithPriority(-1, plugin("reporter")),
+		WithPriority(10, plugin("config")),
+	)
+
+	app.Initialize(&PluginFuncs{
+		StartFunc: func(app *Application) error {
+			go app.Shutdown(nil)
+			return nil
+		},
+	})
+
+	require.NoError(t, app.StartE())
+	require.Equal(t, []string{"logger", "config", "http-server", "metrics", "reporter"}, order)
+}
+
+func Test_ApplicationInitialize_Dependencies(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	order := make([]string, 0)
+	plugin := func(name string) *namedPlugin {
+		return &namedPlugin{
+			PluginFuncs: PluginFuncs{
+				InitializeFunc: func(app *Application) error {
+					order = append(order, name)
+					return nil
+				},
+			},
+			name: name,
+		}
+	}
+
+	app.Initialize(
+		WithDependencies(plugin("api"), "db"),
+		plugin("db"),
+	)
+
+	require.NoError(t, app.RunE())
+	require.Equal(t, []string{"db", "api"}, order)
+}
+
+func Test_ApplicationInitialize_DependencyErrors(t *testing.T) {
+	app := newTestApp(func(err error) {})
+	app.Initialize(WithDependencies(&namedPlugin{name: "api"}, "db"))
+	require.ErrorIs(t, app.RunE(), ErrUnknownDependency)
+
+	app = newTestApp(func(err error) {})
+	app.Initialize(
+		WithDependencies(&namedPlugin{name: "api"}, "db"),
+		WithDependencies(&namedPlugin{name: "db"}, "api"),
+	)
+	require.ErrorIs(t, app.RunE(), ErrDependencyCycle)
+}
+
+func Test_ApplicationWithParallelism(t *testing.T) {
+	app := NewApplication(
+		WithParallelism(2),
+		WithTerminator(func(err error) {}),
+	)
+
+	barrier := sync.WaitGroup{}
+	barrier.Add(2)
+
+	initialized := int32(0)
+	independent := func(name string) Plugin {
+		return &namedPlugin{
+			PluginFuncs: PluginFuncs{
+				InitializeFunc: func(app *Application) error {
+					// both plugins must be initializing at the same time for the barrier to be released
+					barrier.Done()
+					barrier.Wait()
+
+					atomic.AddInt32(&initialized, 1)
+					return nil
+				},
+			},
+			name: name,
+		}
+	}
+
+	app.Initialize(
+		independent("db"),
+		independent("cache"),
+		WithDependencies(&namedPlugin{
+			PluginFuncs: PluginFuncs{
+				InitializeFunc: func(app *Application) error {
+					require.Equal(t, int32(2), atomic.LoadInt32(&initialized), "dependencies not initialized")
+					return nil
+				},
+			},
+			name: "api",
+		}, "db", "cache"),
+	)
+
+	require.NoError(t, app.RunE())
+}
+
+func Test_ApplicationWithParallelism_Error(t *testing.T) {
+	app := NewApplication(
+		WithParallelism(4),
+		WithTerminator(func(err error) {}),
+	)
+
+	counts, executionCountPlugin := countingPlugin()
+
+	app.Initialize(
+		&namedPlugin{
+			PluginFuncs: PluginFuncs{
+				InitializeFunc: func(app *Application) error {
+					return fmt.Errorf("connection refused")
+				},
+			},
+			name: "db",
+		},
+		WithDependencies(executionCountPlugin, "db"),
+	)
+
+	require.EqualError(t, app.RunE(), "db (*lifecycle.namedPlugin) failed to initialize: connection refused")
+	require.Equal(t, 0, counts[initialize], "unexpected initialize count")
+	require.Equal(t, 0, counts[shutdown], "unexpected shutdown count")
+}
+
+// blockingStartPlugin blocks while starting until its context is canceled.
+type blockingStartPlugin struct {
+	*contextPlugin
+	err error
+}
+
+func (p *blockingStartPlugin) StartContext(ctx context.Context, app *Application) error {
+	select {
+	case <-ctx.Done():
+		p.err = ctx.Err()
+	case <-time.After(time.Second):
+	}
+	return nil
+}
+
+func Test_ApplicationWithParallelism_Start(t *testing.T) {
+	app := NewApplication(
+		WithParallelism(2),
+		WithTerminator(func(err error) {}),
+	)
+
+	plugin := &blockingStartPlugin{contextPlugin: newContextPlugin()}
+
+	app.Initialize(
+		plugin,
+		&PluginFuncs{
+			StartFunc: func(app *Application) error {
+				return fmt.Errorf("address already in use")
+			},
+		},
+	)
+
+	require.EqualError(t, app.StartE(), "plugin[1] (*lifecycle.PluginFuncs) failed to start: address already in use")
+	require.ErrorIs(t, plugin.err, context.Canceled)
+}
+
+func Test_ApplicationWithParallelism_Shutdown(t *testing.T) {
+	app := NewApplication(
+		WithParallelism(2),
+		WithTerminator(func(err error) {}),
+	)
+
+	barrier := sync.WaitGroup{}
+	barrier.Add(2)
+
+	stopped := int32(0)
+	dependent := func(name string) Plugin {
+		return WithDependencies(&namedPlugin{
+			PluginFuncs: PluginFuncs{
+				ShutdownFunc: func(app *Application) error {
+					// both plugins must be shutting down at the same time for the barrier to be released
+					barrier.Done()
+					barrier.Wait()
+
+					atomic.AddInt32(&stopped, 1)
+					return nil
+				},
+			},
+			name: name,
+		}, "db")
+	}
+
+	app.Initialize(
+		&namedPlugin{
+			PluginFuncs: PluginFuncs{
+				ShutdownFunc: func(app *Application) error {
+					require.Equal(t, int32(2), atomic.LoadInt32(&stopped), "dependents not shutdown")
+					return nil
+				},
+			},
+			name: "db",
+		},
+		dependent("api"),
+		dependent("worker"),
+	)
+
+	require.NoError(t, app.RunE())
+}
+
+func Test_Group(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	order := make([]string, 0)
+	member := func(name string) *namedPlugin {
+		return &namedPlugin{
+			PluginFuncs: PluginFuncs{
+				StartFunc: func(app *Application) error {
+					order = append(order, "start "+name)
+					return nil
+				},
+				ShutdownFunc: func(app *Application) error {
+					order = append(order, "shutdown "+name)
+					return nil
+				},
+			},
+			name: name,
+		}
+	}
+
+	tracer := member("tracer")
+	tracer.StartFunc = func(app *Application) error {
+		return fmt.Errorf("collector unavailable")
+	}
+
+	app.Initialize(Group("observability", member("logger"), member("metrics"), tracer))
+
+	plugin, ok := app.Plugin("observability")
+	require.True(t, ok, "group not found")
+	require.NotNil(t, plugin)
+
+	err := app.StartE()
+	require.EqualError(t, err, "observability (*lifecycle.group) failed to start: "+
+		"tracer (*lifecycle.namedPlugin) failed to start: collector unavailable")
+	require.Equal(t, []string{
+		"start logger", "start metrics",
+		"shutdown tracer", "shutdown metrics", "shutdown logger",
+	}, order)
+}
+
+func Test_Group_InitializeError(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	counts, executionCountPlugin := countingPlugin()
+
+	app.Initialize(Group("storage", executionCountPlugin, &PluginFuncs{
+		InitializeFunc: func(app *Application) error {
+			return fmt.Errorf("bucket not found")
+		},
+	}))
+
+	err := app.RunE()
+	require.EqualError(t, err, "storage (*lifecycle.group) failed to initialize: "+
+		"plugin[1] (*lifecycle.PluginFuncs) failed to initialize: bucket not found")
+	require.Equal(t, 1, counts[initialize], "unexpected initialize count")
+	require.Equal(t, 1, counts[shutdown], "unexpected shutdown count")
+}
+
+func Test_Group_HealthDuringShutdown(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	g := Group("storage", &healthPlugin{name: "postgres"})
+	require.NoError(t, g.Initialize(app))
+
+	// readiness probes may check the health of the group while it's being shutdown
+	checked := make(chan struct{})
+	go func() {
+		defer close(checked)
+		for i := 0; i < 100; i++ {
+			_ = g.(HealthChecker).Healthy(context.Background())
+		}
+	}()
+
+	require.NoError(t, g.Shutdown(app))
+	<-checked
+}
+
+type validatingPlugin struct {
+	PluginFuncs
+	err error
+}
+
+func (p *validatingPlugin) Validate(app *Application) error {
+	return p.err
+}
+
+func Test_Validator(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	counts, executionCountPlugin := countingPlugin()
+
+	app.Initialize(
+		executionCountPlugin,
+		&validatingPlugin{err: fmt.Errorf("missing DATABASE_URL")},
+		&validatingPlugin{},
+		Group("cache", &validatingPlugin{err: fmt.Errorf("missing REDIS_URL")}),
+	)
+
+	err := app.RunE()
+	require.Equal(t, strings.Join([]string{
+		"plugin[1] (*lifecycle.validatingPlugin) failed to validate: missing DATABASE_URL",
+		"cache (*lifecycle.group) failed to validate: " +
+			"plugin[0] (*lifecycle.validatingPlugin) failed to validate: missing REDIS_URL",
+	}, "\n"), err.Error())
+	require.Equal(t, 0, counts[initialize], "unexpected initialize count")
+	require.Equal(t, 0, counts[shutdown], "unexpected shutdown count")
+}
+
+func Test_Validator_Disabled(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	disabled := func(app *Application) bool {
+		return false
+	}
+
+	app.Initialize(
+		When(disabled, &validatingPlugin{err: fmt.Errorf("missing DATABASE_URL")}),
+		Group("cache", When(disabled, &validatingPlugin{err: fmt.Errorf("missing REDIS_URL")})),
+	)
+
+	require.NoError(t, app.RunE())
+}
+
+func Test_When(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	counts, executionCountPlugin := countingPlugin()
+	disabled, disabledPlugin := countingPlugin()
+	disabledMembers, disabledMember := countingPlugin()
+
+	debug := ContextKey("debug")
+	app.Initialize(
+		&PluginFuncs{
+			InitializeFunc: func(app *Application) error {
+				app.WithValue(debug, false)
+				return nil
+			},
+		},
+		When(func(app *Application) bool {
+			return app.Context().Value(debug) == false
+		}, executionCountPlugin),
+		When(func(app *Application) bool {
+			return app.Context().Value(debug) == true
+		}, disabledPlugin),
+		Group("debug", When(func(app *Application) bool {
+			return app.Context().Value(debug) == true
+		}, disabledMember)),
+	)
+
+	require.NoError(t, app.RunE())
+	require.Equal(t, map[string]int{initialize: 1, run: 1, shutdown: 1}, counts)
+	require.Empty(t, disabled, "disabled plugin unexpectedly invoked")
+	require.Empty(t, disabledMembers, "disabled member unexpectedly invoked")
+}
+
+func Test_Wrap(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	calls := make([]string, 0)
+	trace := func(name string) Middleware {
+		return func(phase Phase, next Invoker) Invoker {
+			return func(ctx context.Context, app *Application) error {
+				calls = append(calls, fmt.Sprintf("%s %s", name, phase))
+				return next(ctx, app)
+			}
+		}
+	}
+
+	app.Initialize(
+		Wrap(&PluginFuncs{
+			RunFunc: func(app *Application) error {
+				panic("nil map")
+			},
+		}, trace("outer"), trace("inner"), Recover),
+	)
+
+	err := app.RunE()
+	require.EqualError(t, err, "plugin[0] (*lifecycle.PluginFuncs) failed to run: "+
+		"recovered from panic during run: nil map")
+	require.Equal(t, []string{
+		"outer initialize", "inner initialize",
+		"outer run", "inner run",
+		"outer shutdown", "inner shutdown",
+	}, calls)
+}
+
+func Test_ApplicationInterceptor(t *testing.T) {
+	calls := make([]string, 0)
+	audit := func(name string) Interceptor {
+		return func(plugin string, phase Phase, next Invoker) Invoker {
+			return func(ctx context.Context, app *Application) error {
+				calls = append(calls, fmt.Sprintf("%s %s %s", name, phase, plugin))
+				return next(ctx, app)
+			}
+		}
+	}
+
+	app := NewApplication(
+		WithInterceptor(audit("first")),
+		WithTerminator(func(err error) {}),
+	)
+	app.AddInterceptor(audit("second"))
+
+	app.Initialize(&namedPlugin{name: "db"})
+	require.NoError(t, app.RunE())
+
+	require.Equal(t, []string{
+		"first initialize db (*lifecycle.namedPlugin)", "second initialize db (*lifecycle.namedPlugin)",
+		"first run db (*lifecycle.namedPlugin)", "second run db (*lifecycle.namedPlugin)",
+		"first shutdown db (*lifecycle.namedPlugin)", "second shutdown db (*lifecycle.namedPlugin)",
+	}, calls)
+}
+
+// phasePlugin records the custom phases it participates in.
+type phasePlugin struct {
+	PluginFuncs
+	name   string
+	phases *[]string
+}
+
+func (p *phasePlugin) HandlePhase(ctx context.Context, app *Application, phase Phase) error {
+	*p.phases = append(*p.phases, fmt.Sprintf("%s %s", phase, p.name))
+	return nil
+}
+
+// shutdownOnPhase shuts down the application once the provided custom phase is reached.
+type shutdownOnPhase struct {
+	PluginFuncs
+	phase Phase
+}
+
+func (p *shutdownOnPhase) HandlePhase(ctx context.Context, app *Application, phase Phase) error {
+	if phase == p.phase {
+		go app.Shutdown(nil)
+	}
+	return nil
+}
+
+func Test_WithPhase(t *testing.T) {
+	phases := make([]string, 0)
+
+	app := NewApplication(
+		WithPhase("warmup", After, PhaseInitialize),
+		WithPhase("ready", After, PhaseStart),
+		WithPhase("drain", Before, PhaseShutdown),
+		WithTerminator(func(err error) {}),
+	)
+
+	app.Initialize(
+		&phasePlugin{name: "db", phases: &phases},
+		&PluginFuncs{},
+		&phasePlugin{
+			PluginFuncs: PluginFuncs{
+				StartFunc: func(app *Application) error {
+					phases = append(phases, "start http")
+					return nil
+				},
+			},
+			name:   "http",
+			phases: &phases,
+		},
+		&shutdownOnPhase{phase: "ready"},
+	)
+
+	require.NoError(t, app.StartE())
+
+	require.Equal(t, []string{
+		"warmup db", "warmup http",
+		"start http",
+		"ready db", "ready http",
+		"drain http", "drain db",
+	}, phases)
+}
+
+func Test_WithPhase_Unsupported(t *testing.T) {
+	require.PanicsWithValue(t, "lifecycle: cannot add phase cleanup after shutdown", func() {
+		WithPhase("cleanup", After, PhaseShutdown)
+	})
+}
+
+func Test_ApplicationBeforeAfterStart(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	order := make([]string, 0)
+	app.BeforeStart(func(ctx context.Context) error {
+		order = append(order, "before")
+		return nil
+	})
+	app.AfterStart(func(ctx context.Context) error {
+		order = append(order, "after")
+		return fmt.Errorf("failed to register with load balancer")
+	})
+
+	app.Initialize(&PluginFuncs{
+		StartFunc: func(app *Application) error {
+			order = append(order, "start")
+			return nil
+		},
+	})
+
+	require.EqualError(t, app.StartE(), "failed to register with load balancer")
+	require.Equal(t, []string{"before", "start", "after"}, order)
+}
+
+type readyPlugin struct {
+	PluginFuncs
+	ready chan struct{}
+}
+
+func (p *readyPlugin) Ready() <-chan struct{} {
+	return p.ready
+}
+
+func Test_ApplicationReady(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	server := &readyPlugin{ready: make(chan struct{})}
+	server.StartFunc = func(app *Application) error {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			close(server.ready)
+		}()
+		return nil
+	}
+
+	app.AfterStart(func(ctx context.Context) error {
+		select {
+		case <-server.ready:
+		default:
+			return fmt.Errorf("started before the server was ready")
+		}
+
+		go app.Shutdown(nil)
+		return nil
+	})
+
+	app.Initialize(Group("servers", server))
+
+	require.NoError(t, app.StartE())
+
+	select {
+	case <-app.Ready():
+	default:
+		t.Fatal("application was not ready")
+	}
+}
+
+func Test_Group_Ready(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	server := &readyPlugin{ready: make(chan struct{})}
+	g := Group("servers", server)
+	require.NoError(t, g.Initialize(app))
+
+	ready := g.(Readiness).Ready()
+	require.Equal(t, ready, g.(Readiness).Ready(), "readiness rebuilt for each call")
+
+	close(server.ready)
+	<-ready
+
+	// the members are waited on again once the group is initialized again
+	require.NoError(t, g.Shutdown(app))
+	server.ready = make(chan struct{})
+	require.NoError(t, g.Initialize(app))
+
+	select {
+	case <-g.(Readiness).Ready():
+		t.Fatal("group ready before its members were started again")
+	default:
+	}
+}
+
+func Test_ApplicationReady_Timeout(t *testing.T) {
+	app := NewApplication(WithTerminator(func(err error) {}), WithStartTimeout(10*time.Millisecond))
+
+	app.Initialize(&readyPlugin{ready: make(chan struct{})})
+
+	err := app.StartE()
+	require.ErrorIs(t, err, ErrStartTimeout)
+	require.EqualError(t, err, "start did not complete within 10ms: plugin[0] (*lifecycle.readyPlugin)")
+
+	select {
+	case <-app.Ready():
+		t.Fatal("application unexpectedly ready")
+	default:
+	}
+}
+
+func Test_ApplicationMetadata(t *testing.T) {
+	app := NewApplication(
+		WithTerminator(func(err error) {}),
+		WithName("api"),
+		WithVersion("v1.2.3"),
+		WithMetadata("region", "us-east-1"),
+	)
+
+	metadata := app.Metadata()
+	require.Equal(t, "api", metadata.Name)
+	require.Equal(t, "v1.2.3", metadata.Version)
+	require.Equal(t, "us-east-1", metadata.Values["region"])
+	require.Equal(t, runtime.Version(), metadata.Values["go.version"])
+
+	fromContext, ok := MetadataFromContext(app.Context())
+	require.True(t, ok, "metadata not attached")
+	require.Equal(t, metadata, fromContext)
+
+	// defaults are populated from the build info of the test binary
+	metadata = (&Application{}).Metadata()
+	require.Equal(t, "go-lifecycle.test", metadata.Name)
+	require.NotEmpty(t, metadata.Version)
+}
+
+type healthPlugin struct {
+	PluginFuncs
+	name string
+	err  error
+}
+
+func (p *healthPlugin) Name() string {
+	return p.name
+}
+
+func (p *healthPlugin) Healthy(_ context.Context) error {
+	return p.err
+}
+
+func Test_ApplicationHealth(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	var report HealthReport
+	app.Initialize(
+		&healthPlugin{name: "postgres"},
+		Group("caches", &healthPlugin{name: "redis", err: fmt.Errorf("connection refused")}),
+		&PluginFuncs{
+			RunFunc: func(app *Application) error {
+				report = app.Health(context.Background())
+				return nil
+			},
+		},
+	)
+
+	require.NoError(t, app.RunE())
+
+	require.False(t, report.Healthy())
+	require.Len(t, report.Plugins, 2)
+	require.NoError(t, report.Plugins["postgres"])
+	require.EqualError(t, report.Err(), "caches: redis (*lifecycle.healthPlugin): connection refused")
+
+	require.Empty(t, app.Health(context.Background()).Plugins, "shutdown plugins checked")
+}
+
+func Test_ApplicationRestart(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	counts, executionCountPlugin := countingPlugin()
+	app.Initialize(executionCountPlugin)
+
+	require.ErrorIs(t, app.Restart(), ErrNotStarted)
+
+	ctx := app.Context()
+	restarted := make(chan error, 1)
+
+	app.AfterStart(func(_ context.Context) error {
+		go func() {
+			restarted <- app.Restart()
+			app.Shutdown(nil)
+		}()
+		return nil
+	})
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, <-restarted)
+	require.Error(t, ctx.Err(), "previous context was not canceled")
+
+	require.Equal(t, 2, counts[initialize], "unexpected initialize count")
+	require.Equal(t, 2, counts[start], "unexpected start count")
+	require.Equal(t, 2, counts[shutdown], "unexpected shutdown count")
+
+	require.ErrorIs(t, app.Restart(), ErrAlreadyTerminated)
+}
+
+func Test_ApplicationRestart_OnShutdown(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	var hooks int32
+	app.Initialize(&PluginFuncs{})
+	app.OnShutdown(func(_ context.Context) error {
+		atomic.AddInt32(&hooks, 1)
+		return nil
+	})
+
+	restarted := make(chan error, 1)
+	app.AfterStart(func(_ context.Context) error {
+		go func() {
+			err := app.Restart()
+			if atomic.LoadInt32(&hooks) != 0 {
+				err = fmt.Errorf("shutdown hook invoked by restart")
+			}
+
+			restarted <- err
+			app.Shutdown(nil)
+		}()
+		return nil
+	})
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, <-restarted)
+	require.Equal(t, int32(1), atomic.LoadInt32(&hooks), "unexpected shutdown hook count")
+}
+
+func Test_ApplicationRestart_Release(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	var events, transitions, deferred, hooks int32
+	listeners := make([]net.Listener, 0)
+
+	app.Initialize(&PluginFuncs{
+		InitializeFunc: func(app *Application) error {
+			app.AddHook(func(event Event) {
+				if event.Kind == EventPhaseEnter && event.Phase == PhaseShutdown {
+					atomic.AddInt32(&events, 1)
+				}
+			})
+
+			app.OnStateChange(func(_, to State) {
+				if to == StateShutdown {
+					atomic.AddInt32(&transitions, 1)
+				}
+			})
+
+			app.Defer(func() error {
+				atomic.AddInt32(&deferred, 1)
+				return nil
+			})
+
+			app.OnShutdown(func(_ context.Context) error {
+				atomic.AddInt32(&hooks, 1)
+				return nil
+			})
+
+			listener, err := app.Listen("tcp", "127.0.0.1:0")
+			listeners = append(listeners, listener)
+			return err
+		},
+	})
+
+	restarted := make(chan error, 1)
+	app.AfterStart(func(_ context.Context) error {
+		go func() {
+			err := app.Restart()
+			if err == nil && !listeners[0].(*managedListener).isClosed() {
+				err = fmt.Errorf("listener not closed by restart")
+			}
+
+			restarted <- err
+			app.Shutdown(nil)
+		}()
+		return nil
+	})
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, <-restarted)
+
+	// registrations made by the plugin before the restart are released rather than accumulating
+	require.Equal(t, int32(1), atomic.LoadInt32(&events), "unexpected hook count")
+	require.Equal(t, int32(1), atomic.LoadInt32(&transitions), "unexpected state listener count")
+	require.Equal(t, int32(2), atomic.LoadInt32(&deferred), "unexpected deferred count")
+	require.Equal(t, int32(2), atomic.LoadInt32(&hooks), "unexpected shutdown hook count")
+	require.Len(t, listeners, 2)
+	require.Empty(t, app.Listeners())
+}
+
+func Test_ApplicationRestart_Ready(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	restarting := make(chan bool, 2)
+	app.Initialize(&PluginFuncs{
+		ShutdownFunc: func(app *Application) error {
+			select {
+			case <-app.Ready():
+				restarting <- true
+			default:
+				restarting <- false
+			}
+			return nil
+		},
+	})
+
+	restarted := make(chan error, 1)
+	app.AfterStart(func(_ context.Context) error {
+		go func() {
+			err := app.Restart()
+			select {
+			case <-app.Ready():
+			default:
+				err = fmt.Errorf("not ready once restarted")
+			}
+
+			restarted <- err
+			app.Shutdown(nil)
+		}()
+		return nil
+	})
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, <-restarted)
+	require.False(t, <-restarting, "ready while restarting")
+}
+
+func Test_ApplicationRestart_Error(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	restarts := 0
+	app.Initialize(&PluginFuncs{
+		InitializeFunc: func(app *Application) error {
+			if restarts > 0 {
+				return fmt.Errorf("invalid configuration")
+			}
+			return nil
+		},
+	})
+
+	app.AfterStart(func(_ context.Context) error {
+		go func() {
+			restarts++
+			_ = app.Restart()
+		}()
+		return nil
+	})
+
+	require.EqualError(t, app.StartE(), "plugin[0] (*lifecycle.PluginFuncs) failed to initialize: invalid configuration")
+	require.Equal(t, ShutdownFailed, app.ShutdownReason().Kind)
+}
+
+type exitingPlugin struct {
+	PluginFuncs
+	exited chan error
+}
+
+func (p *exitingPlugin) Exited() <-chan error {
+	return p.exited
+}
+
+func Test_Supervise(t *testing.T) {
+	warnings := make([]string, 0)
+
+	app := NewApplication(
+		WithHook(func(event Event) {
+			if event.Kind == EventWarning {
+				warnings = append(warnings, event.Err.Error())
+			}
+		}),
+		WithTerminator(func(err error) {}),
+	)
+
+	starts := 0
+	worker := &exitingPlugin{}
+	worker.StartFunc = func(app *Application) error {
+		starts++
+		worker.exited = make(chan error, 1)
+
+		switch starts {
+		case 1:
+			worker.exited <- fmt.Errorf("connection reset")
+		case 2:
+			return fmt.Errorf("connection refused")
+		default:
+			app.Shutdown(nil)
+		}
+		return nil
+	}
+
+	app.Initialize(Supervise(RestartPolicy{Mode: RestartOnFailure, Backoff: time.Millisecond}, worker))
+
+	require.NoError(t, app.StartE())
+	require.Equal(t, 3, starts, "unexpected start count")
+	require.Equal(t, []string{
+		"plugin[0] (*lifecycle.exitingPlugin) failed to start: connection reset",
+		"plugin[0] (*lifecycle.exitingPlugin) failed to start: connection refused",
+	}, warnings)
+}
+
+func Test_Supervise_Release(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	var transitions int32
+	starts := 0
+	worker := &exitingPlugin{}
+	worker.StartFunc = func(app *Application) error {
+		starts++
+		worker.exited = make(chan error, 1)
+
+		app.OnStateChange(func(_, to State) {
+			if to == StateShutdown {
+				atomic.AddInt32(&transitions, 1)
+			}
+		})
+
+		if starts == 1 {
+			worker.exited <- fmt.Errorf("connection reset")
+		} else {
+			app.Shutdown(nil)
+		}
+		return nil
+	}
+
+	app.Initialize(Supervise(RestartPolicy{Mode: RestartOnFailure, Backoff: time.Millisecond}, worker))
+
+	require.NoError(t, app.StartE())
+	require.Equal(t, 2, starts, "unexpected start count")
+
+	// the state listener registered by the first start is released once the plugin is restarted
+	require.Equal(t, int32(1), atomic.LoadInt32(&transitions), "unexpected state listener count")
+}
+
+func Test_Supervise_Never(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	worker := &exitingPlugin{exited: make(chan error, 1)}
+	worker.StartFunc = func(app *Application) error {
+		worker.exited <- fmt.Errorf("connection reset")
+		return nil
+	}
+
+	app.Initialize(worker)
+
+	require.EqualError(t, app.StartE(), "plugin[0] (*lifecycle.exitingPlugin) failed to start: connection reset")
+	require.Equal(t, ShutdownFailed, app.ShutdownReason().Kind)
+}
+
+func Test_RestartPolicy_Backoff(t *testing.T) {
+	policy := RestartPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	require.Equal(t, time.Second, policy.backoff(0))
+	require.Equal(t, 2*time.Second, policy.backoff(1))
+	require.Equal(t, 4*time.Second, policy.backoff(2))
+	require.Equal(t, 5*time.Second, policy.backoff(3))
+	require.Equal(t, time.Minute, RestartPolicy{}.backoff(10))
+}
+
+func Test_ApplicationLogger(t *testing.T) {
+	output := &strings.Builder{}
+	logger := slog.New(slog.NewTextHandler(output, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey || attr.Key == "duration" {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+
+	app := NewApplication(WithLogger(logger), WithTerminator(func(err error) {}))
+	require.Equal(t, logger, app.Logger())
+
+	app.Initialize(&PluginFuncs{
+		RunFunc: func(app *Application) error {
+			return fmt.Errorf("something went wrong")
+		},
+	})
+
+	require.Error(t, app.RunE())
+
+	lines := strings.Split(output.String(), "\n")
+	require.Contains(t, lines, "level=INFO msg=\"entering phase\" phase=initialize")
+	require.Contains(t, lines, "level=DEBUG msg=\"plugin completed phase\" phase=initialize "+
+		"plugin=\"plugin[0] (*lifecycle.PluginFuncs)\"")
+	require.Contains(t, lines, "level=ERROR msg=\"plugin failed\" phase=run "+
+		"plugin=\"plugin[0] (*lifecycle.PluginFuncs)\" "+
+		"error=\"plugin[0] (*lifecycle.PluginFuncs) failed to run: something went wrong\"")
+}
+
+func Test_ApplicationTimings(t *testing.T) {
+	output := &strings.Builder{}
+	logger := slog.New(slog.NewTextHandler(output, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey || attr.Key == "duration" {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+
+	app := NewApplication(WithLogger(logger), WithTimingSummary(), WithTerminator(func(err error) {}))
+
+	app.Initialize(
+		&namedPlugin{name: "fast"},
+		&namedPlugin{
+			PluginFuncs: PluginFuncs{
+				InitializeFunc: func(app *Application) error {
+					time.Sleep(10 * time.Millisecond)
+					return nil
+				},
+			},
+			name: "slow",
+		},
+	)
+
+	require.NoError(t, app.RunE())
+
+	timings := app.Timings()
+	require.Len(t, timings, 6)
+	require.Equal(t, "fast (*lifecycle.namedPlugin)", timings[0].Plugin)
+	require.Equal(t, PhaseInitialize, timings[0].Phase)
+	require.GreaterOrEqual(t, timings[1].Duration, 10*time.Millisecond)
+
+	summary := make([]string, 0)
+	for _, line := range strings.Split(output.String(), "\n") {
+		if strings.Contains(line, "plugin timing") {
+			summary = append(summary, line)
+		}
+	}
+
+	require.Len(t, summary, 2)
+	require.Equal(t, "level=INFO msg=\"plugin timing\" phase=initialize "+
+		"plugin=\"slow (*lifecycle.namedPlugin)\"", summary[0])
+}
+
+func Test_ApplicationTimings_Restart(t *testing.T) {
+	app := newTestApp(func(err error) {})
+	app.Initialize(&namedPlugin{name: "database"})
+
+	restarted := make(chan error, 1)
+	app.AfterStart(func(_ context.Context) error {
+		go func() {
+			defer app.Shutdown(nil)
+
+			for i := 0; i < 3; i++ {
+				if err := app.Restart(); err != nil {
+					restarted <- err
+					return
+				}
+			}
+			restarted <- nil
+		}()
+		return nil
+	})
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, <-restarted)
+
+	// only the most recent timing of each phase is retained
+	phases := make([]Phase, 0)
+	for _, timing := range app.Timings() {
+		phases = append(phases, timing.Phase)
+	}
+	require.Equal(t, []Phase{PhaseInitialize, PhaseStart, PhaseShutdown}, phases)
+}
+
+func Test_ApplicationProgress(t *testing.T) {
+	progress := make([]string, 0)
+
+	app := NewApplication(
+		WithProgress(ProgressFunc(func(p Progress) {
+			progress = append(progress, p.String())
+		})),
+		WithTerminator(func(err error) {}),
+	)
+
+	app.Initialize(
+		&namedPlugin{name: "postgres"},
+		When(func(app *Application) bool { return false }, &namedPlugin{name: "debug"}),
+		&namedPlugin{name: "redis"},
+	)
+
+	require.NoError(t, app.RunE())
+	require.Equal(t, []string{
+		"plugin 1/3 (postgres) initializing",
+		"plugin 2/3 (debug) initializing",
+		"plugin 3/3 (redis) initializing",
+		"plugin 1/2 (postgres) running",
+		"plugin 2/2 (redis) running",
+		"plugin 1/2 (redis) shutting down",
+		"plugin 2/2 (postgres) shutting down",
+	}, progress)
+}
+
+func Test_ApplicationHistory(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	app.Initialize(&PluginFuncs{})
+	require.NoError(t, app.RunE())
+
+	history := app.History()
+	require.NotEmpty(t, history)
+	require.Equal(t, &Transition{From: StateInvalid, To: StateInitial}, history[0].Transition)
+
+	last := history[len(history)-1]
+	require.NotNil(t, last.Event)
+	require.Equal(t, PhaseTerminated, last.Event.Phase)
+}
+
+func Test_ApplicationHistory_Size(t *testing.T) {
+	app := NewApplication(WithHistorySize(2), WithTerminator(func(err error) {}))
+
+	app.Initialize()
+	require.NoError(t, app.RunE())
+
+	history := app.History()
+	require.Len(t, history, 2)
+	require.Equal(t, &Transition{From: StateShutdown, To: StateTerminated}, history[0].Transition)
+	require.Equal(t, PhaseTerminated, history[1].Event.Phase)
+
+	disabled := NewApplication(WithHistorySize(0))
+	require.Empty(t, disabled.History())
+}
+
+func Test_ApplicationDump(t *testing.T) {
+	app := newTestApp(func(err error) {})
+	app.Initialize(&namedPlugin{name: "postgres"})
+
+	output := &strings.Builder{}
+	DumpStacks(output)(app)
+
+	dump := output.String()
+	require.True(t, strings.HasPrefix(dump, "lifecycle: application is initial\n"), "unexpected header")
+	require.Contains(t, dump, "state invalid -> initial")
+	require.Contains(t, dump, "plugin initialize postgres (*lifecycle.namedPlugin)")
+	require.Contains(t, dump, "goroutine ")
+	require.Contains(t, dump, "Test_ApplicationDump")
+
+	require.NoError(t, app.RunE())
+}
+
+// syncBuilder is a strings.Builder that's safe for concurrent use.
+type syncBuilder struct {
+	mu      sync.Mutex
+	builder strings.Builder
+}
+
+func (b *syncBuilder) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.builder.Write(p)
+}
+
+func (b *syncBuilder) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.builder.String()
+}
+
+func Test_ApplicationShutdownWatchdog(t *testing.T) {
+	warnings := make(chan Event, 1)
+	output := &syncBuilder{}
+
+	app := NewApplication(
+		WithHook(func(event Event) {
+			if event.Kind == EventWarning {
+				warnings <- event
+			}
+		}),
+		WithShutdownWatchdog(Watchdog{Threshold: 10 * time.Millisecond, Output: output}),
+		WithTerminator(func(err error) {}),
+	)
+
+	app.Initialize(&namedPlugin{
+		PluginFuncs: PluginFuncs{
+			ShutdownFunc: func(app *Application) error {
+				time.Sleep(50 * time.Millisecond)
+				return nil
+			},
+		},
+		name: "kafka",
+	})
+
+	require.NoError(t, app.RunE())
+
+	warning := <-warnings
+	require.Equal(t, PhaseShutdown, warning.Phase)
+	require.Equal(t, "kafka (*lifecycle.namedPlugin)", warning.Plugin)
+
+	slow := &SlowError{}
+	require.ErrorAs(t, warning.Err, &slow)
+	require.Equal(t, []string{"kafka (*lifecycle.namedPlugin)"}, slow.Plugins)
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(output.String(), "goroutine ")
+	}, time.Second, time.Millisecond)
+}
+
+func Test_ApplicationStartupWatchdog(t *testing.T) {
+	warnings := make([]Event, 0)
+	warningsMu := sync.Mutex{}
+
+	app := NewApplication(
+		WithHook(func(event Event) {
+			if event.Kind == EventWarning {
+				warningsMu.Lock()
+				warnings = append(warnings, event)
+				warningsMu.Unlock()
+			}
+		}),
+		WithStartupWatchdog(10*time.Millisecond),
+		WithTerminator(func(err error) {}),
+	)
+
+	app.Initialize(
+		&namedPlugin{name: "fast"},
+		&namedPlugin{
+			PluginFuncs: PluginFuncs{
+				InitializeFunc: func(app *Application) error {
+					time.Sleep(50 * time.Millisecond)
+					return nil
+				},
+			},
+			name: "postgres",
+		},
+	)
+
+	require.NoError(t, app.RunE())
+
+	warningsMu.Lock()
+	defer warningsMu.Unlock()
+
+	require.Len(t, warnings, 1)
+	require.Equal(t, PhaseInitialize, warnings[0].Phase)
+	require.Equal(t, "postgres (*lifecycle.namedPlugin)", warnings[0].Plugin)
+	require.Contains(t, warnings[0].Err.Error(), "initialize still running after ")
+}
+
+func Test_ApplicationShutdown_Idempotent(t *testing.T) {
+	terminated := int32(0)
+
+	app := NewApplication(
+		WithHook(func(event Event) {
+			if event.Phase == PhaseTerminated {
+				atomic.AddInt32(&terminated, 1)
+			}
+		}),
+		WithTerminator(func(err error) {}),
+	)
+
+	counts, executionCountPlugin := countingPlugin()
+	app.Initialize(executionCountPlugin)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			app.Shutdown(fmt.Errorf("something went wrong"))
+		}()
+
+		go func() {
+			defer wg.Done()
+			_ = app.shutdown(nil)
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equal(t, 1, counts[shutdown], "unexpected shutdown count")
+	require.Equal(t, int32(1), atomic.LoadInt32(&terminated), "unexpected terminated count")
+	require.Equal(t, StateTerminated, app.State())
+}
+
+func Test_ApplicationInitialize_ShutdownInitializedOnly(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	first, firstPlugin := countingPlugin()
+	last, lastPlugin := countingPlugin()
+	failed := 0
+
+	app.Initialize(
+		firstPlugin,
+		&PluginFuncs{
+			InitializeFunc: func(app *Application) error {
+				return fmt.Errorf("something went wrong")
+			},
+			ShutdownFunc: func(app *Application) error {
+				failed++
+				return nil
+			},
+		},
+		lastPlugin,
+	)
+
+	require.Error(t, app.RunE(), "application did not fail with error")
+
+	require.Equal(t, 1, first[initialize], "unexpected initialize count")
+	require.Equal(t, 1, first[shutdown], "unexpected shutdown count")
+	require.Equal(t, 0, failed, "failed plugin unexpectedly shutdown")
+	require.Equal(t, 0, last[initialize], "unexpected initialize count")
+	require.Equal(t, 0, last[shutdown], "uninitialized plugin unexpectedly shutdown")
+}
+
+func Test_ApplicationShutdown_JoinedErrors(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	app.Initialize(
+		&PluginFuncs{
+			ShutdownFunc: func(app *Application) error {
+				return fmt.Errorf("failed to close connection")
+			},
+		},
+		&PluginFuncs{
+			RunFunc: func(app *Application) error {
+				return ErrRunOrStart
+			},
+			ShutdownFunc: func(app *Application) error {
+				return fmt.Errorf("failed to flush buffer")
+			},
+		},
+	)
+
+	err := app.RunE()
+	require.ErrorIs(t, err, ErrRunOrStart)
+	require.Equal(t, strings.Join([]string{
+		"plugin[1] (*lifecycle.PluginFuncs) failed to run: " + ErrRunOrStart.Error(),
+		"plugin[1] (*lifecycle.PluginFuncs) failed to shutdown: failed to flush buffer",
+		"plugin[0] (*lifecycle.PluginFuncs) failed to shutdown: failed to close connection",
+	}, "\n"), err.Error())
+	require.Equal(t, err.Error(), app.Err().Error())
+}
+
+func Test_ApplicationListen(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	// reserve a free port for the application to listen on
+	reserved, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := reserved.Addr().String()
+	require.NoError(t, reserved.Close())
+
+	var listener net.Listener
+	app.Initialize(
+		&PluginFuncs{
+			InitializeFunc: func(app *Application) error {
+				listener, err = app.Listen("tcp", addr)
+				return err
+			},
+		},
+		&PluginFuncs{
+			RunFunc: func(app *Application) error {
+				// the open listener is handed to later callers
+				handed, err := app.Listen("tcp", addr)
+				require.NoError(t, err)
+				require.Same(t, listener, handed)
+				require.Equal(t, []net.Listener{listener}, app.Listeners())
+
+				// ephemeral ports are never shared
+				ephemeral, err := app.Listen("tcp", "127.0.0.1:0")
+				require.NoError(t, err)
+				require.NotSame(t, listener, ephemeral)
+				require.NoError(t, ephemeral.Close())
+
+				require.Equal(t, []net.Listener{listener}, app.Listeners())
+				return nil
+			},
+		},
+	)
+
+	require.NoError(t, app.RunE())
+	require.Empty(t, app.Listeners())
+
+	_, err = listener.Accept()
+	require.ErrorIs(t, err, net.ErrClosed, "listener not closed at shutdown")
+
+	_, err = app.Listen("tcp", addr)
+	require.ErrorIs(t, err, ErrAlreadyTerminated)
+}
+
+func Test_ApplicationListen_Conflict(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	conflict, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conflict.Close()
+
+	app.Initialize(&PluginFuncs{
+		InitializeFunc: func(app *Application) error {
+			_, err := app.Listen("tcp", conflict.Addr().String())
+			return err
+		},
+	})
+
+	var pluginErr *PluginError
+	require.ErrorAs(t, app.RunE(), &pluginErr)
+	require.Equal(t, PhaseInitialize, pluginErr.Phase)
+}
+
+func Test_ApplicationTrack(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	release := make(chan struct{})
+	completed := make(chan struct{})
+	var admitted bool
+	var inFlight int
+	var idle <-chan struct{}
+
+	app.Initialize(&PluginFuncs{
+		StartFunc: func(app *Application) error {
+			done, ok := app.Track()
+			require.True(t, ok)
+
+			go func() {
+				defer done()
+				defer close(completed)
+				<-release
+			}()
+			return nil
+		},
+		ShutdownFunc: func(app *Application) error {
+			// plugins are only shutdown once the work in-flight completed
+			select {
+			case <-completed:
+			default:
+				return errors.New("work still in-flight")
+			}
+			return nil
+		},
+	})
+
+	go func() {
+		<-app.Ready()
+		go app.Shutdown(nil)
+		for app.State() < StateShutdown {
+			time.Sleep(time.Millisecond)
+		}
+
+		_, admitted = app.Track()
+		inFlight = app.InFlight()
+		idle = app.Idle()
+		close(release)
+	}()
+
+	require.NoError(t, app.StartE())
+	require.False(t, admitted, "work admitted during shutdown")
+	require.Equal(t, 1, inFlight)
+	require.Equal(t, 0, app.InFlight())
+
+	select {
+	case <-idle:
+	default:
+		t.Fatal("idle while work was in-flight not closed once it completed")
+	}
+
+	select {
+	case <-app.Idle():
+	default:
+		t.Fatal("idle not closed while no work is in-flight")
+	}
+}
+
+func Test_ApplicationTrack_Timeout(t *testing.T) {
+	var warnings []error
+	app := NewApplication(
+		WithTerminator(func(err error) {}),
+		WithWorkTimeout(10*time.Millisecond),
+		WithHook(func(event Event) {
+			if event.Kind == EventWarning {
+				warnings = append(warnings, event.Err)
+			}
+		}),
+	)
+
+	app.Initialize(&PluginFuncs{
+		StartFunc: func(app *Application) error {
+			// the work is never completed
+			_, ok := app.Track()
+			require.True(t, ok)
+			return nil
+		},
+	})
+
+	go func() {
+		<-app.Ready()
+		app.Shutdown(nil)
+	}()
+
+	require.NoError(t, app.StartE())
+	require.Len(t, warnings, 1)
+	require.ErrorIs(t, warnings[0], context.DeadlineExceeded)
+	require.EqualError(t, warnings[0], "1 units of work in-flight: context deadline exceeded")
+}
+
+func Test_ApplicationWithPreShutdownDelay(t *testing.T) {
+	app := NewApplication(
+		WithTerminator(func(err error) {}),
+		WithPreShutdownDelay(50*time.Millisecond),
+	)
+
+	var requested, stopped time.Time
+	var state State
+
+	app.Initialize(&PluginFuncs{
+		StartFunc: func(app *Application) error {
+			app.Go(func(ctx context.Context) error {
+				<-ctx.Done()
+
+				// go-routines keep running during the delay
+				state = app.State()
+				return nil
+			})
+			return nil
+		},
+		ShutdownFunc: func(app *Application) error {
+			stopped = time.Now()
+			return nil
+		},
+	})
+
+	go func() {
+		<-app.Ready()
+		requested = time.Now()
+		app.Shutdown(nil)
+	}()
+
+	require.NoError(t, app.StartE())
+	require.Equal(t, StateShutdown, state)
+	require.GreaterOrEqual(t, stopped.Sub(requested), 50*time.Millisecond)
+}
+
+func Test_ApplicationWithPreShutdownDelay_Track(t *testing.T) {
+	app := NewApplication(
+		WithTerminator(func(err error) {}),
+		WithPreShutdownDelay(50*time.Millisecond),
+	)
+
+	var completed, stopped time.Time
+	app.Initialize(&PluginFuncs{
+		ShutdownFunc: func(app *Application) error {
+			stopped = time.Now()
+			return nil
+		},
+	})
+
+	tracked := make(chan bool, 1)
+	var state State
+	go func() {
+		<-app.Ready()
+		app.Shutdown(nil)
+		<-app.Terminating()
+
+		// work arriving during the delay is still admitted, and is waited on before plugins are shutdown
+		done, ok := app.Track()
+		state = app.State()
+		tracked <- ok
+
+		time.Sleep(100 * time.Millisecond)
+		completed = time.Now()
+		done()
+	}()
+
+	require.NoError(t, app.StartE())
+	require.True(t, <-tracked, "work rejected during the delay")
+	require.Equal(t, StateStarted, state)
+	require.True(t, stopped.After(completed), "plugin shutdown before the work completed")
+}
+
+type drainingPlugin struct {
+	PluginFuncs
+	name  string
+	err   error
+	calls *[]string
+}
+
+func (p *drainingPlugin) Name() string {
+	return p.name
+}
+
+func (p *drainingPlugin) Drain(_ context.Context, _ *Application) error {
+	*p.calls = append(*p.calls, "drain "+p.name)
+	return p.err
+}
+
+func (p *drainingPlugin) Shutdown(_ *Application) error {
+	*p.calls = append(*p.calls, "shutdown "+p.name)
+	return nil
+}
+
+func Test_ApplicationDrain(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	calls := make([]string, 0)
+	app.Initialize(
+		&PluginFuncs{
+			ShutdownFunc: func(app *Application) error {
+				calls = append(calls, "shutdown database")
+				return nil
+			},
+		},
+		&drainingPlugin{name: "consumer", calls: &calls},
+		Group("servers",
+			&drainingPlugin{name: "grpc", calls: &calls},
+			&drainingPlugin{name: "http", calls: &calls},
+		),
+	)
+
+	require.NoError(t, app.RunE())
+	require.Equal(t, []string{
+		"drain http",
+		"drain grpc",
+		"drain consumer",
+		"shutdown http",
+		"shutdown grpc",
+		"shutdown consumer",
+		"shutdown database",
+	}, calls)
+}
+
+func Test_ApplicationDrain_Error(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	var phases []Phase
+	app.AddHook(func(event Event) {
+		if event.Kind == EventPhaseEnter {
+			phases = append(phases, event.Phase)
+		}
+	})
+
+	calls := make([]string, 0)
+	app.Initialize(&drainingPlugin{name: "http", calls: &calls, err: errors.New("connections still open")})
+
+	err := app.RunE()
+
+	var pluginErr *PluginError
+	require.ErrorAs(t, err, &pluginErr)
+	require.Equal(t, PhaseDrain, pluginErr.Phase)
+	require.Equal(t, []string{"drain http", "shutdown http"}, calls)
+	require.Contains(t, phases, PhaseDrain)
+}
+
+func Test_ApplicationPluginStatuses(t *testing.T) {
+	app := newTestApp(func(err error) {})
+
+	var running []PluginStatus
+	calls := make([]string, 0)
+	app.Initialize(
+		&namedPlugin{name: "database"},
+		&PluginFuncs{
+			RunFunc: func(app *Application) error {
+				running = app.PluginStatuses()
+				return nil
+			},
+		},
+		&drainingPlugin{name: "server", calls: &calls},
+	)
+
+	require.NoError(t, app.RunE())
+
+	require.Len(t, running, 3)
+	require.Equal(t, "database", running[0].Name)
+	require.Equal(t, "*lifecycle.namedPlugin", running[0].Type)
+	require.Equal(t, "initialized", running[0].Status)
+	require.Equal(t, []Phase{PhaseInitialize, PhaseRun, PhaseStart, PhaseShutdown}, running[0].Phases)
+	require.Equal(t, []Phase{PhaseInitialize, PhaseRun, PhaseStart, PhaseDrain, PhaseShutdown}, running[2].Phases)
+	require.Contains(t, running[0].Timings, PhaseInitialize)
+	require.NotContains(t, running[0].Timings, PhaseShutdown)
+	require.Equal(t, "plugin[1]", running[1].Name)
+
+	statuses := app.PluginStatuses()
+	require.Equal(t, "shutdown", statuses[0].Status)
+	require.Contains(t, statuses[0].Timings, PhaseShutdown)
+}
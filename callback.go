@@ -0,0 +1,42 @@
+package lifecycle
+
+import (
+	"context"
+)
+
+// BeforeStart registers a function that's invoked once, just before plugins are started. Should the function return an
+// error, the application is shutdown without starting any plugins.
+func (app *Application) BeforeStart(fn func(ctx context.Context) error) {
+	app.on.Do(app.init)
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.beforeStart = append(app.beforeStart, fn)
+}
+
+// AfterStart registers a function that's invoked once every plugin has been started. This is useful for flipping a
+// readiness gauge or logging that the application is listening. Should the function return an error, the application
+// is shutdown. AfterStart functions are not invoked when the application is shutdown while starting.
+func (app *Application) AfterStart(fn func(ctx context.Context) error) {
+	app.on.Do(app.init)
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.afterStart = append(app.afterStart, fn)
+}
+
+// runCallbacks invokes each of the provided callbacks in the order they were registered, stopping at the first error.
+func (app *Application) runCallbacks(callbacks *[]func(ctx context.Context) error) error {
+	app.mu.RLock()
+	fns := (*callbacks)[:len(*callbacks):len(*callbacks)]
+	app.mu.RUnlock()
+
+	for _, fn := range fns {
+		if err := fn(app.Context()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
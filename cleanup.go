@@ -0,0 +1,105 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// shutdownHook adapts a function registered using OnShutdown into a plugin that only participates in shutdown. Hooks
+// are registered alongside plugins so they're shutdown in order, but are not listed as plugins (see Plugins).
+type shutdownHook struct {
+	PluginFuncs
+	name string
+	fn   func(ctx context.Context) error
+	by   ownership
+}
+
+func (h *shutdownHook) Name() string {
+	return h.name
+}
+
+func (h *shutdownHook) InitializeContext(_ context.Context, _ *Application) error {
+	return nil
+}
+
+func (h *shutdownHook) RunContext(_ context.Context, _ *Application) error {
+	return nil
+}
+
+func (h *shutdownHook) StartContext(_ context.Context, _ *Application) error {
+	return nil
+}
+
+func (h *shutdownHook) ShutdownContext(ctx context.Context, _ *Application) error {
+	return h.fn(ctx)
+}
+
+var _ PluginContext = &shutdownHook{}
+var _ Named = &shutdownHook{}
+
+// OnShutdown registers a function that's invoked when the application is shutdown. This allows cleanup work discovered
+// at runtime (for example, by a request handler) to be registered without writing a plugin. Functions are treated like
+// plugins that were initialized at the time of registration, so they're invoked before any plugin registered prior to
+// them is shutdown. Functions registered once the application has begun shutting down are not invoked.
+func (app *Application) OnShutdown(fn func(ctx context.Context) error) {
+	app.on.Do(app.init)
+
+	app.mu.Lock()
+	hook := &shutdownHook{name: fmt.Sprintf("shutdown hook[%d]", app.shutdownHooks), fn: fn, by: app.owner()}
+	app.shutdownHooks++
+	app.mu.Unlock()
+
+	// registration only fails when a plugin was explicitly given the name of the hook
+	registrations, err := app.register([]Plugin{hook})
+	if err != nil {
+		app.report(newEvent(EventWarning, "", "", time.Now(), fmt.Errorf("failed to register shutdown hook: %w", err)))
+		return
+	}
+
+	for _, reg := range registrations {
+		reg.setStatus(statusInitialized)
+	}
+}
+
+// Defer registers a function that's invoked when the application is shutdown. Deferred functions are invoked in the
+// reverse order they were registered, once every plugin has been shutdown. This formalizes acquiring a resource during
+// Initialize and releasing it at shutdown, similar to Go's defer statement.
+func (app *Application) Defer(fn func() error) {
+	app.on.Do(app.init)
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.deferred = append(app.deferred, owned[func() error]{value: fn, by: app.owner()})
+}
+
+// runDeferred invokes each deferred function in the reverse order they were registered, returning any errors.
+func (app *Application) runDeferred() []error {
+	errs := make([]error, 0)
+
+	for {
+		app.mu.Lock()
+		n := len(app.deferred)
+		if n == 0 {
+			app.mu.Unlock()
+			return errs
+		}
+
+		fn := app.deferred[n-1].value
+		app.deferred = app.deferred[:n-1]
+		app.mu.Unlock()
+
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+}
+
+// WithCloser sets the key on the underlying application context to the provided closer and defers its Close method
+// until the application is shutdown. Closers are closed in the reverse order they were registered.
+func (app *Application) WithCloser(key interface{}, closer io.Closer) {
+	app.WithValue(key, closer)
+	app.Defer(closer.Close)
+}
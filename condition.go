@@ -0,0 +1,35 @@
+package lifecycle
+
+// Enabler is an optional interface plugins can implement to determine whether they should participate in the
+// lifecycle. Enabled is invoked just before the plugin would be initialized, allowing it to inspect configuration
+// provided by previously initialized plugins. Disabled plugins are not initialized, started, or shutdown.
+type Enabler interface {
+	Enabled(app *Application) bool
+}
+
+// When wraps the provided plugin, only enabling it when the provided condition holds. This allows plugins to be skipped
+// based on environment variables or configuration (for example, disabling a debug server in production).
+func When(cond func(app *Application) bool, plugin Plugin) Plugin {
+	return &conditionPlugin{
+		pluginWrapper: pluginWrapper{plugin},
+		cond:          cond,
+	}
+}
+
+type conditionPlugin struct {
+	pluginWrapper
+	cond func(app *Application) bool
+}
+
+func (p *conditionPlugin) Enabled(app *Application) bool {
+	return p.cond(app)
+}
+
+// enabled returns true when every Enabler in the chain of wrapped plugins is enabled.
+func enabled(app *Application, plugin Plugin) bool {
+	_, disabled := findPlugin(plugin, func(p Plugin) bool {
+		e, ok := p.(Enabler)
+		return ok && !e.Enabled(app)
+	})
+	return !disabled
+}
@@ -0,0 +1,113 @@
+package lifecycle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dependent is an optional interface plugins can implement to declare the names of the plugins (see Named) they depend
+// on. Dependencies are initialized and started before the plugins depending on them, and shutdown after. Plugins are
+// only considered independent of one another (and may be initialized concurrently, see WithParallelism) when neither
+// depends on the other.
+type Dependent interface {
+	Dependencies() []string
+}
+
+// WithDependencies wraps the provided plugin, declaring the names of the plugins it depends on.
+func WithDependencies(plugin Plugin, names ...string) Plugin {
+	return &dependentPlugin{
+		pluginWrapper: pluginWrapper{plugin},
+		names:         names,
+	}
+}
+
+type dependentPlugin struct {
+	pluginWrapper
+	names []string
+}
+
+func (p *dependentPlugin) Dependencies() []string {
+	return p.names
+}
+
+func dependencies(plugin Plugin) []string {
+	p, ok := findPlugin(plugin, func(p Plugin) bool {
+		_, ok := p.(Dependent)
+		return ok
+	})
+	if !ok {
+		return nil
+	}
+	return p.(Dependent).Dependencies()
+}
+
+// resolveDependencies resolves the dependencies of each registration in the batch against the registrations that were
+// previously registered and those within the batch.
+func resolveDependencies(registered, batch []*registration) error {
+	named := make(map[string]*registration, len(registered)+len(batch))
+	for _, reg := range append(registered[:len(registered):len(registered)], batch...) {
+		named[reg.name] = reg
+	}
+
+	for _, reg := range batch {
+		for _, name := range dependencies(reg.plugin) {
+			dependency, ok := named[name]
+			if !ok {
+				return fmt.Errorf("%w: %s depends on %q", ErrUnknownDependency, reg, name)
+			}
+
+			reg.dependencies = append(reg.dependencies, dependency)
+		}
+	}
+
+	return nil
+}
+
+// sortDependencies orders the batch of registrations such that each registration follows its dependencies. The order
+// of the batch is otherwise retained.
+func sortDependencies(batch []*registration) ([]*registration, error) {
+	pending := make(map[*registration]bool, len(batch))
+	for _, reg := range batch {
+		pending[reg] = true
+	}
+
+	sorted := make([]*registration, 0, len(batch))
+	for len(sorted) < len(batch) {
+		next := nextReady(batch, pending)
+		if next == nil {
+			remaining := make([]*registration, 0, len(pending))
+			for _, reg := range batch {
+				if pending[reg] {
+					remaining = append(remaining, reg)
+				}
+			}
+
+			return nil, fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(describeRegistrations(remaining), ", "))
+		}
+
+		delete(pending, next)
+		sorted = append(sorted, next)
+	}
+
+	return sorted, nil
+}
+
+// nextReady returns the first pending registration whose dependencies are no longer pending.
+func nextReady(batch []*registration, pending map[*registration]bool) *registration {
+	for _, reg := range batch {
+		if !pending[reg] {
+			continue
+		}
+
+		ready := true
+		for _, dependency := range reg.dependencies {
+			ready = ready && !pending[dependency]
+		}
+
+		if ready {
+			return reg
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package lifecycle
+
+import (
+	"context"
+	"sync"
+)
+
+// Drainer is an optional interface plugins can implement to separate draining from shutting down. When the
+// application is shutdown, every plugin implementing Drainer is drained before any plugin is shutdown. Plugins should
+// stop accepting new work and wait for the work in-flight to complete (such as a server no longer accepting
+// connections and waiting for in-flight requests) while continuing to hold the resources the work depends on, which
+// are then released during Shutdown. This avoids the ordering problems that arise when a plugin releases resources
+// while other plugins are still finishing work that depends on them.
+type Drainer interface {
+	Drain(ctx context.Context, app *Application) error
+}
+
+func findDrainer(plugin Plugin) (Drainer, bool) {
+	p, ok := findPlugin(plugin, func(p Plugin) bool {
+		_, ok := p.(Drainer)
+		return ok
+	})
+	if !ok {
+		return nil, false
+	}
+	return p.(Drainer), true
+}
+
+func drainPlugin(ctx context.Context, app *Application, plugin Plugin) error {
+	drainer, ok := findDrainer(plugin)
+	if !ok {
+		return nil
+	}
+	return drainer.Drain(ctx, app)
+}
+
+// drainPlugins drains each of the provided registrations implementing Drainer in the reverse order they were
+// registered, returning all errors encountered. Like shutdown, independent plugins are drained concurrently when
+// configured using WithParallelism while plugins are still drained before the plugins they depend on.
+func (app *Application) drainPlugins(ctx context.Context, registrations []*registration) []error {
+	drainers := make([]*registration, 0, len(registrations))
+	for _, reg := range registrations {
+		if _, ok := findDrainer(reg.plugin); ok {
+			drainers = append(drainers, reg)
+		}
+	}
+
+	if len(drainers) == 0 {
+		return nil
+	}
+
+	errs := make([]error, 0)
+	errsMu := sync.Mutex{}
+
+	started := app.enter(PhaseDrain)
+
+	// drain errors are collected rather than interrupting the draining of the remaining plugins
+	_ = schedule(reversed(drainers), app.parallelism, dependentsOf(drainers), func(reg *registration) error {
+		if err := app.invoke(ctx, PhaseDrain, 0, reg, drainPlugin); err != nil {
+			errsMu.Lock()
+			errs = append(errs, err)
+			errsMu.Unlock()
+		}
+		return nil
+	})
+
+	app.exit(PhaseDrain, started, nil)
+	return errs
+}
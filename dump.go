@@ -0,0 +1,39 @@
+package lifecycle
+
+import (
+	"fmt"
+	"io"
+	"runtime/pprof"
+)
+
+// Dump writes the state and history of the application (see History), followed by the stack of every go-routine, to
+// the provided writer. This helps diagnose plugins that hang while starting or shutting down.
+func (app *Application) Dump(w io.Writer) error {
+	app.on.Do(app.init)
+
+	if _, err := fmt.Fprintf(w, "lifecycle: application is %s\n\n", app.State()); err != nil {
+		return err
+	}
+
+	for _, record := range app.History() {
+		if _, err := fmt.Fprintln(w, record); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+
+	return pprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+// DumpStacks returns a SignalHandler that dumps the application to the provided writer (see Dump) without terminating
+// it. This is typically registered for SIGQUIT, which otherwise terminates the process after dumping its stacks.
+//
+//	app.HandleSignal(syscall.SIGQUIT, lifecycle.DumpStacks(os.Stderr))
+func DumpStacks(w io.Writer) SignalHandler {
+	return func(app *Application) {
+		_ = app.Dump(w)
+	}
+}
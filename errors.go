@@ -1,7 +1,10 @@
 package lifecycle
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 )
 
 var (
@@ -9,4 +12,152 @@ var (
 	ErrInitializeAfterStartup = fmt.Errorf("cannot initialize application after startup")
 	// ErrRunOrStart is provided to shutdown when both Run and Start are invoked on an Application.
 	ErrRunOrStart = fmt.Errorf("cannot start and run an application in the same execution context")
+	// ErrForcedShutdown is provided to shutdown when a repeated signal forces the application to quit.
+	ErrForcedShutdown = fmt.Errorf("shutdown forced by repeated signal")
+	// ErrNotInitialized is provided to shutdown when Run or Start is invoked before Initialize.
+	ErrNotInitialized = fmt.Errorf("cannot startup application before it has been initialized")
+	// ErrAlreadyTerminated is returned when an operation is requested of an application that has been shutdown.
+	ErrAlreadyTerminated = fmt.Errorf("application has already been terminated")
+	// ErrNotStarted is returned when the application is restarted before it has been started and is ready.
+	ErrNotStarted = fmt.Errorf("application has not been started")
+	// ErrDuplicatePlugin is provided to shutdown when a plugin (or a plugin with the same name) is registered twice.
+	ErrDuplicatePlugin = fmt.Errorf("plugin registered more than once")
+	// ErrUnknownDependency is provided to shutdown when a plugin depends on a plugin that has not been registered.
+	ErrUnknownDependency = fmt.Errorf("plugin depends on an unknown plugin")
+	// ErrDependencyCycle is provided to shutdown when the dependencies of plugins form a cycle.
+	ErrDependencyCycle = fmt.Errorf("plugin dependencies form a cycle")
+
+	// ErrInitializeTimeout matches TimeoutErrors encountered while initializing plugins.
+	ErrInitializeTimeout = fmt.Errorf("initialize timed out")
+	// ErrStartTimeout matches TimeoutErrors encountered while starting plugins.
+	ErrStartTimeout = fmt.Errorf("start timed out")
+	// ErrShutdownTimeout matches TimeoutErrors encountered while shutting down plugins.
+	ErrShutdownTimeout = fmt.Errorf("shutdown timed out")
 )
+
+var timeoutErrs = map[Phase]error{
+	PhaseInitialize: ErrInitializeTimeout,
+	PhaseStart:      ErrStartTimeout,
+	PhaseShutdown:   ErrShutdownTimeout,
+}
+
+// TimeoutError is provided to shutdown when plugins fail to complete a phase within its configured timeout.
+type TimeoutError struct {
+	// Phase is the phase of the lifecycle that timed out.
+	Phase Phase
+	// Timeout is the amount of time plugins were given to complete the phase.
+	Timeout time.Duration
+	// Plugins describes the plugins that were still running when the deadline was exceeded.
+	Plugins []string
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s did not complete within %s: %s", e.Phase, e.Timeout, strings.Join(e.Plugins, ", "))
+}
+
+// Is allows the error to be matched against the timeout sentinel of its phase (such as ErrShutdownTimeout) using
+// errors.Is.
+func (e *TimeoutError) Is(target error) bool {
+	sentinel, ok := timeoutErrs[e.Phase]
+	return ok && target == sentinel
+}
+
+// SlowError is reported to the configured hooks as an EventWarning when plugins take longer than expected to complete
+// a phase. Unlike a TimeoutError, the application continues waiting on the plugins.
+type SlowError struct {
+	// Phase is the phase of the lifecycle that is taking longer than expected.
+	Phase Phase
+	// Elapsed is how long the phase had been running when the error was reported.
+	Elapsed time.Duration
+	// Plugins describes the plugins that were still running when the error was reported.
+	Plugins []string
+}
+
+func (e *SlowError) Error() string {
+	if len(e.Plugins) == 0 {
+		return fmt.Sprintf("%s still running after %s", e.Phase, e.Elapsed)
+	}
+	return fmt.Sprintf("%s still running after %s: %s", e.Phase, e.Elapsed, strings.Join(e.Plugins, ", "))
+}
+
+// PluginError is provided to shutdown when a plugin returns an error from one of its lifecycle methods. It identifies
+// which plugin failed and during which phase of the lifecycle.
+type PluginError struct {
+	// Plugin describes the plugin that returned the error.
+	Plugin string
+	// Phase is the phase of the lifecycle the plugin failed in.
+	Phase Phase
+	// Err is the error returned by the plugin.
+	Err error
+}
+
+func (e *PluginError) Error() string {
+	return fmt.Sprintf("%s failed to %s: %s", e.Plugin, e.Phase, e.Err)
+}
+
+func (e *PluginError) Unwrap() error {
+	return e.Err
+}
+
+// KeyCollisionError is reported when a plugin sets a context key previously set by another plugin, shadowing the
+// earlier value.
+type KeyCollisionError struct {
+	// Key is the context key that was set more than once.
+	Key interface{}
+	// Previous describes the plugin that provided the shadowed value.
+	Previous string
+	// Current describes the plugin that provided the new value.
+	Current string
+}
+
+func (e *KeyCollisionError) Error() string {
+	return fmt.Sprintf("%v provided by %s shadows the value provided by %s", e.Key, e.Current, e.Previous)
+}
+
+// ExitCoder is implemented by errors that declare the exit code the process should terminate with. Should an
+// application terminate with an error implementing ExitCoder (or wrapping one), the default terminator exits the
+// process using the provided code.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// ExitError associates an exit code with an error.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// NewExitError wraps the provided error, associating it with the given exit code.
+func NewExitError(code int, err error) *ExitError {
+	return &ExitError{Code: code, Err: err}
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// ExitCode returns the exit code associated with the error.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+var _ ExitCoder = &ExitError{}
+
+// ExitCode returns the exit code the process should terminate with given the provided error. A nil error maps to 0,
+// errors implementing ExitCoder provide their own code, and all other errors map to 1.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+
+	return 1
+}
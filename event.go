@@ -0,0 +1,145 @@
+package lifecycle
+
+import (
+	"context"
+	"time"
+)
+
+// Phase identifies a phase of the application lifecycle.
+type Phase string
+
+const (
+	// PhaseValidate is the phase in which plugins validate their configuration, prior to any being initialized.
+	PhaseValidate Phase = "validate"
+	// PhaseInitialize is the phase in which plugins are initialized.
+	PhaseInitialize Phase = "initialize"
+	// PhaseRun is the phase in which plugins are run as a short lived job.
+	PhaseRun Phase = "run"
+	// PhaseStart is the phase in which plugins are started as a long running agent.
+	PhaseStart Phase = "start"
+	// PhaseReload is the phase in which plugins are reloaded without tearing down the application.
+	PhaseReload Phase = "reload"
+	// PhaseRestart is the phase in which plugins are shutdown and started again without terminating the application.
+	PhaseRestart Phase = "restart"
+	// PhaseDrain is the phase in which plugins stop accepting new work and finish the work in-flight, prior to any
+	// plugin being shutdown (see Drainer).
+	PhaseDrain Phase = "drain"
+	// PhaseShutdown is the phase in which plugins are shutdown.
+	PhaseShutdown Phase = "shutdown"
+	// PhaseTerminated is the final phase, reached once all plugins have been shutdown.
+	PhaseTerminated Phase = "terminated"
+)
+
+// EventKind distinguishes the different kinds of events delivered to hooks.
+type EventKind int
+
+const (
+	// EventPlugin describes the outcome of a single plugin's phase, whether it succeeded or failed.
+	EventPlugin EventKind = iota
+	// EventPhaseEnter marks the application entering a phase.
+	EventPhaseEnter
+	// EventPhaseExit marks the application completing a phase. Phases aborted due to a plugin error do not complete.
+	EventPhaseExit
+	// EventWarning describes a recoverable problem (such as a KeyCollisionError) that did not interrupt the lifecycle.
+	EventWarning
+)
+
+var eventKindNames = map[EventKind]string{
+	EventPlugin:     "plugin",
+	EventPhaseEnter: "enter",
+	EventPhaseExit:  "exit",
+	EventWarning:    "warning",
+}
+
+func (k EventKind) String() string {
+	if name, ok := eventKindNames[k]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Event describes the outcome of a lifecycle step. Events are delivered to hooks so observability tooling can inspect
+// lifecycle transitions without needing to parse strings.
+type Event struct {
+	// Kind is the kind of event.
+	Kind EventKind
+	// Phase is the phase of the lifecycle the event occurred in.
+	Phase Phase
+	// Plugin describes the plugin the event pertains to. Empty for application level events.
+	Plugin string
+	// Time is when the event occurred.
+	Time time.Time
+	// StartedAt is when the step the event describes began.
+	StartedAt time.Time
+	// Duration is how long the step the event describes took. For phase exit events, the duration spans the entire
+	// phase. For the terminated phase, the duration spans the lifetime of the application.
+	Duration time.Duration
+	// Err is the error encountered during the step, if any.
+	Err error
+}
+
+func newEvent(kind EventKind, phase Phase, plugin string, startedAt time.Time, err error) Event {
+	now := time.Now()
+
+	return Event{
+		Kind:      kind,
+		Phase:     phase,
+		Plugin:    plugin,
+		Time:      now,
+		StartedAt: startedAt,
+		Duration:  now.Sub(startedAt),
+		Err:       err,
+	}
+}
+
+// Hook is used to observe state transitions and log semi-fatal errors encountered along the way.
+type Hook func(event Event)
+
+// report invokes each of the configured hooks with the provided event.
+func (app *Application) report(event Event) {
+	app.logEvent(event)
+	app.history.add(Record{Time: event.Time, Event: &event})
+
+	app.mu.RLock()
+	hooks := valuesOf(app.hooks)
+	app.mu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(event)
+	}
+}
+
+// enter reports the application entering the provided phase and returns the time it began.
+func (app *Application) enter(phase Phase) time.Time {
+	now := time.Now()
+	app.report(newEvent(EventPhaseEnter, phase, "", now, nil))
+
+	return now
+}
+
+// exit reports the application completing the provided phase.
+func (app *Application) exit(phase Phase, startedAt time.Time, err error) {
+	app.report(newEvent(EventPhaseExit, phase, "", startedAt, err))
+}
+
+// invoke calls the phase of the registered plugin through the configured interceptors, reporting the outcome to the
+// configured hooks and recording how long the plugin took (see Timings).
+func (app *Application) invoke(
+	ctx context.Context, phase Phase, timeout time.Duration, reg *registration, fn pluginFunc,
+) error {
+	started := time.Now()
+
+	untrack := app.track(phase, reg, started)
+	defer untrack()
+
+	stopWatchdog := app.watchStartup(phase, reg, started)
+	defer stopWatchdog()
+
+	err := app.invokeWithin(ctx, phase, timeout, reg, app.intercept(phase, reg, fn))
+
+	event := newEvent(EventPlugin, phase, reg.String(), started, err)
+	app.recordTiming(event)
+	app.report(event)
+
+	return err
+}
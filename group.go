@@ -0,0 +1,206 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Group bundles the provided plugins into a single plugin with its own name. Members are initialized, run, and started
+// in the order they were provided (see Prioritizer), and shutdown in the reverse order. Errors returned by members are
+// attributed to both the member and the group. Should a member fail to initialize, the members that were previously
+// initialized are shutdown. Disabled members (see Enabler) are skipped.
+func Group(name string, plugins ...Plugin) Plugin {
+	return &group{
+		name:      name,
+		members:   prioritize(plugins),
+		readyOnce: &sync.Once{},
+		ready:     make(chan struct{}),
+	}
+}
+
+type group struct {
+	name    string
+	members []Plugin
+
+	// initialized holds the indexes of the members that successfully initialized. Health checks and readiness probes
+	// may read it while the group is initialized or shutdown, so it's guarded by mu.
+	mu          sync.Mutex
+	initialized []int
+
+	// ready combines the readiness of the initialized members, which is only built once (see Ready). Both are replaced
+	// each time the group is initialized, so restarting the application waits for the members started again.
+	readyOnce *sync.Once
+	ready     chan struct{}
+}
+
+// initializedMembers returns a snapshot of the indexes of the members that successfully initialized.
+func (g *group) initializedMembers() []int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return append([]int(nil), g.initialized...)
+}
+
+func (g *group) Name() string {
+	return g.name
+}
+
+// wrap attributes the error returned by the member at the provided index.
+func (g *group) wrap(phase Phase, i int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &PluginError{
+		Plugin: describePlugin(pluginName(i, g.members[i]), g.members[i]),
+		Phase:  phase,
+		Err:    err,
+	}
+}
+
+func (g *group) each(ctx context.Context, app *Application, phase Phase, fn pluginFunc) error {
+	for _, i := range g.initializedMembers() {
+		if err := fn(ctx, app, g.members[i]); err != nil {
+			return g.wrap(phase, i, err)
+		}
+	}
+	return nil
+}
+
+func (g *group) InitializeContext(ctx context.Context, app *Application) error {
+	g.mu.Lock()
+	g.readyOnce = &sync.Once{}
+	g.ready = make(chan struct{})
+	g.mu.Unlock()
+
+	for i, member := range g.members {
+		if !enabled(app, member) {
+			continue
+		}
+
+		if err := initializePlugin(ctx, app, member); err != nil {
+			return errors.Join(g.wrap(PhaseInitialize, i, err), g.ShutdownContext(ctx, app))
+		}
+
+		g.mu.Lock()
+		g.initialized = append(g.initialized, i)
+		g.mu.Unlock()
+	}
+	return nil
+}
+
+func (g *group) RunContext(ctx context.Context, app *Application) error {
+	return g.each(ctx, app, PhaseRun, runPlugin)
+}
+
+func (g *group) StartContext(ctx context.Context, app *Application) error {
+	return g.each(ctx, app, PhaseStart, startPlugin)
+}
+
+func (g *group) ShutdownContext(ctx context.Context, app *Application) error {
+	g.mu.Lock()
+	initialized := g.initialized
+	g.initialized = nil
+	g.mu.Unlock()
+
+	errs := make([]error, 0)
+	for j := len(initialized); j > 0; j-- {
+		i := initialized[j-1]
+		errs = append(errs, g.wrap(PhaseShutdown, i, shutdownPlugin(ctx, app, g.members[i])))
+	}
+	return errors.Join(errs...)
+}
+
+func (g *group) Drain(ctx context.Context, app *Application) error {
+	initialized := g.initializedMembers()
+
+	errs := make([]error, 0)
+	for j := len(initialized); j > 0; j-- {
+		i := initialized[j-1]
+		errs = append(errs, g.wrap(PhaseDrain, i, drainPlugin(ctx, app, g.members[i])))
+	}
+	return errors.Join(errs...)
+}
+
+func (g *group) Validate(app *Application) error {
+	errs := make([]error, 0, len(g.members))
+	for i, member := range g.members {
+		if !enabled(app, member) {
+			continue
+		}
+
+		errs = append(errs, g.wrap(PhaseValidate, i, validatePlugin(app.Context(), app, member)))
+	}
+	return errors.Join(errs...)
+}
+
+func (g *group) Healthy(ctx context.Context) error {
+	initialized := g.initializedMembers()
+
+	errs := make([]error, 0, len(initialized))
+	for _, i := range initialized {
+		if err := checkHealth(ctx, g.members[i]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", describePlugin(pluginName(i, g.members[i]), g.members[i]), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Ready returns the same channel each time it's called, closed once each initialized member is ready. The members are
+// waited on by a single go-routine, started when Ready is first called.
+func (g *group) Ready() <-chan struct{} {
+	g.mu.Lock()
+	once, ready := g.readyOnce, g.ready
+	g.mu.Unlock()
+
+	once.Do(func() {
+		channels := make([]<-chan struct{}, 0)
+		for _, i := range g.initializedMembers() {
+			channels = append(channels, readiness(g.members[i])...)
+		}
+
+		go func() {
+			defer close(ready)
+
+			for _, ch := range channels {
+				<-ch
+			}
+		}()
+	})
+	return ready
+}
+
+func (g *group) Reload(app *Application) error {
+	return g.each(app.Context(), app, PhaseReload, reloadPlugin)
+}
+
+func (g *group) HandlePhase(ctx context.Context, app *Application, phase Phase) error {
+	return g.each(ctx, app, phase, handlePhase(phase))
+}
+
+func (g *group) Initialize(app *Application) error {
+	return g.InitializeContext(app.Context(), app)
+}
+
+func (g *group) Run(app *Application) error {
+	return g.RunContext(app.Context(), app)
+}
+
+func (g *group) Start(app *Application) error {
+	return g.StartContext(app.Context(), app)
+}
+
+func (g *group) Shutdown(app *Application) error {
+	return g.ShutdownContext(app.Context(), app)
+}
+
+var _ PluginContext = &group{}
+var _ Reloader = &group{}
+var _ Validator = &group{}
+var _ Readiness = &group{}
+var _ HealthChecker = &group{}
+var _ Named = &group{}
+var _ PhaseHandler = &group{}
+var _ Drainer = &group{}
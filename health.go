@@ -0,0 +1,97 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// HealthChecker is an optional interface plugins can implement to report their health (for example, whether a
+// database connection is still usable). Healthy returns nil when the plugin is healthy, and an error describing the
+// problem otherwise. Implementations should respect the deadline of the provided context.
+type HealthChecker interface {
+	Healthy(ctx context.Context) error
+}
+
+// checksHealth returns true when the plugin (or any plugin it wraps) implements HealthChecker.
+func checksHealth(plugin Plugin) bool {
+	_, ok := findPlugin(plugin, func(p Plugin) bool {
+		_, ok := p.(HealthChecker)
+		return ok
+	})
+	return ok
+}
+
+// checkHealth checks each HealthChecker in the chain of wrapped plugins.
+func checkHealth(ctx context.Context, plugin Plugin) error {
+	errs := make([]error, 0)
+	findPlugin(plugin, func(p Plugin) bool {
+		if h, ok := p.(HealthChecker); ok {
+			errs = append(errs, h.Healthy(ctx))
+		}
+		return false
+	})
+	return errors.Join(errs...)
+}
+
+// HealthReport aggregates the health of the plugins implementing HealthChecker.
+type HealthReport struct {
+	// Plugins maps the name of each checked plugin to the error it reported, which is nil when the plugin is healthy.
+	Plugins map[string]error
+}
+
+// Healthy returns true when every checked plugin reported that it is healthy.
+func (r HealthReport) Healthy() bool {
+	return r.Err() == nil
+}
+
+// Err joins the errors reported by unhealthy plugins, ordered by plugin name. Nil is returned when every checked
+// plugin is healthy.
+func (r HealthReport) Err() error {
+	names := make([]string, 0, len(r.Plugins))
+	for name, err := range r.Plugins {
+		if err != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	errs := make([]error, len(names))
+	for i, name := range names {
+		errs[i] = fmt.Errorf("%s: %w", name, r.Plugins[name])
+	}
+	return errors.Join(errs...)
+}
+
+// Health checks the health of each initialized plugin implementing HealthChecker, returning the aggregated report.
+// Plugins are checked concurrently using the provided context. Plugins that have not been initialized, or have been
+// shutdown, are not checked.
+func (app *Application) Health(ctx context.Context) HealthReport {
+	checked := make([]*registration, 0)
+	for _, reg := range app.registered() {
+		status := reg.getStatus()
+		if (status == statusInitialized || status == statusStarted) && checksHealth(reg.plugin) {
+			checked = append(checked, reg)
+		}
+	}
+
+	errs := make([]error, len(checked))
+
+	var wg sync.WaitGroup
+	for i, reg := range checked {
+		wg.Add(1)
+		go func(i int, reg *registration) {
+			defer wg.Done()
+			errs[i] = checkHealth(ctx, reg.plugin)
+		}(i, reg)
+	}
+	wg.Wait()
+
+	report := HealthReport{Plugins: make(map[string]error, len(checked))}
+	for i, reg := range checked {
+		report.Plugins[reg.name] = errs[i]
+	}
+	return report
+}
@@ -0,0 +1,97 @@
+package lifecycle
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultHistorySize is the number of records retained when the size of the history has not been configured.
+const defaultHistorySize = 256
+
+// Transition describes the application moving from one state to another.
+type Transition struct {
+	From State
+	To   State
+}
+
+// Record is an entry in the history of the application. Each record describes either a state transition or an event
+// reported to the configured hooks.
+type Record struct {
+	// Time is when the record was made.
+	Time time.Time
+	// Transition describes the state transition, if the record describes one.
+	Transition *Transition
+	// Event describes the lifecycle event, if the record describes one.
+	Event *Event
+}
+
+func (r Record) String() string {
+	at := r.Time.Format(time.RFC3339Nano)
+
+	switch {
+	case r.Transition != nil:
+		return fmt.Sprintf("%s state %s -> %s", at, r.Transition.From, r.Transition.To)
+	case r.Event != nil:
+		description := fmt.Sprintf("%s %s %s", at, r.Event.Kind, r.Event.Phase)
+		if r.Event.Plugin != "" {
+			description += " " + r.Event.Plugin
+		}
+
+		if r.Event.Err != nil {
+			description += ": " + r.Event.Err.Error()
+		}
+		return description
+	default:
+		return at
+	}
+}
+
+// History returns the most recent records of the application's lifecycle, oldest first. This is useful for debugging
+// and for exposing the lifecycle through admin endpoints. By default, the last 256 records are retained (see
+// WithHistorySize).
+func (app *Application) History() []Record {
+	app.on.Do(app.init)
+	return app.history.records()
+}
+
+// history is a ring buffer of records.
+type history struct {
+	mu      sync.Mutex
+	entries []Record
+	next    int
+	full    bool
+}
+
+func newHistory(size int) *history {
+	if size < 0 {
+		size = 0
+	}
+
+	return &history{
+		entries: make([]Record, size),
+	}
+}
+
+func (h *history) add(record Record) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.entries) == 0 {
+		return
+	}
+
+	h.entries[h.next] = record
+	h.next = (h.next + 1) % len(h.entries)
+	h.full = h.full || h.next == 0
+}
+
+func (h *history) records() []Record {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.full {
+		return append([]Record(nil), h.entries[:h.next]...)
+	}
+	return append(append([]Record(nil), h.entries[h.next:]...), h.entries[:h.next]...)
+}
@@ -0,0 +1,153 @@
+package lifecycle
+
+import (
+	"net"
+	"sync"
+)
+
+// managedListener tracks whether a listener owned by the application has been closed, ensuring it is only closed once
+// regardless of whether it was closed by a server or by the application during shutdown.
+type managedListener struct {
+	net.Listener
+	network string
+	address string
+
+	// inherited marks a listener passed from the process this one was upgraded from (see Upgrade) that has yet to be
+	// handed out
+	inherited bool
+	// activated marks a listener passed to the process by systemd socket activation, which is matched against the
+	// requested address by the address it is bound to
+	activated bool
+
+	once   sync.Once
+	closed chan struct{}
+	err    error
+}
+
+func newManagedListener(listener net.Listener, network, address string) *managedListener {
+	return &managedListener{
+		Listener: listener,
+		network:  network,
+		address:  address,
+		closed:   make(chan struct{}),
+	}
+}
+
+func (l *managedListener) Close() error {
+	l.once.Do(func() {
+		l.err = l.Listener.Close()
+		close(l.closed)
+	})
+	return l.err
+}
+
+func (l *managedListener) isClosed() bool {
+	select {
+	case <-l.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// reusable returns true when the listener can be handed out for the provided network and address. Listeners bound to
+// an ephemeral port are only handed out once inherited.
+func (l *managedListener) reusable(network, address string) bool {
+	if l.activated {
+		return !l.isClosed() && l.boundTo(network, address)
+	}
+
+	if l.network != network || l.address != address || l.isClosed() {
+		return false
+	}
+
+	if l.inherited {
+		return true
+	}
+
+	_, port, err := net.SplitHostPort(address)
+	return err == nil && port != "0"
+}
+
+// boundTo returns true when the listener is bound to the provided network and address. Unspecified hosts (such as in
+// ":8080") only match listeners bound to every interface.
+func (l *managedListener) boundTo(network, address string) bool {
+	switch actual := l.Addr().(type) {
+	case *net.TCPAddr:
+		requested, err := net.ResolveTCPAddr(network, address)
+		if err != nil || requested.Port != actual.Port {
+			return false
+		}
+
+		if requested.IP == nil || requested.IP.IsUnspecified() {
+			return actual.IP.IsUnspecified()
+		}
+		return requested.IP.Equal(actual.IP)
+	case *net.UnixAddr:
+		return network == actual.Net && address == actual.Name
+	default:
+		return false
+	}
+}
+
+// Listen announces on the provided network and address (see net.Listen), returning a listener owned by the
+// application. Listening during initialization allows port conflicts to fail the application before any plugin is
+// started. Should the application already own an open listener for the same network and address, it is returned
+// instead of binding a new one, allowing the listener to be handed from the plugin that created it to the server that
+// accepts on it. When the process was started by Upgrade, the listener inherited for the same network and address is
+// returned. Similarly, when the process was started using systemd socket activation, the activated socket bound to the
+// address is returned. Listeners are closed once every plugin has been shutdown, if they were not closed beforehand
+// (such as by a server that's been shutdown). Once the application has begun shutting down, ErrAlreadyTerminated is
+// returned.
+func (app *Application) Listen(network, address string) (net.Listener, error) {
+	app.on.Do(app.init)
+
+	if app.State() >= StateShutdown {
+		return nil, ErrAlreadyTerminated
+	}
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	for _, l := range app.sockets {
+		if l.reusable(network, address) {
+			l.inherited = false
+			return l, nil
+		}
+	}
+
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	managed := newManagedListener(listener, network, address)
+	app.sockets = append(app.sockets, managed)
+	app.deferred = append(app.deferred, owned[func() error]{value: managed.Close, by: app.owner()})
+	return managed, nil
+}
+
+// Listeners returns the listeners owned by the application that have yet to be closed (see Listen).
+func (app *Application) Listeners() []net.Listener {
+	open := app.openListeners()
+
+	listeners := make([]net.Listener, len(open))
+	for i, l := range open {
+		listeners[i] = l
+	}
+	return listeners
+}
+
+// openListeners returns the listeners owned by the application that have yet to be closed.
+func (app *Application) openListeners() []*managedListener {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+
+	listeners := make([]*managedListener, 0, len(app.sockets))
+	for _, l := range app.sockets {
+		if !l.isClosed() {
+			listeners = append(listeners, l)
+		}
+	}
+	return listeners
+}
@@ -0,0 +1,67 @@
+package lifecycle
+
+import (
+	"context"
+	"log/slog"
+	"os"
+)
+
+// Logger returns the logger configured using WithLogger. When no logger has been configured, slog.Default is returned.
+// Plugins can use this to log using the same logger as the application.
+func (app *Application) Logger() *slog.Logger {
+	app.on.Do(app.init)
+
+	if app.logger == nil {
+		return slog.Default()
+	}
+	return app.logger
+}
+
+// logEvent logs the provided event using the configured logger. Nothing is logged unless a logger has been configured.
+// Successful plugin phases are logged at the debug level, while failures are logged as errors.
+func (app *Application) logEvent(event Event) {
+	if app.logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{slog.String("phase", string(event.Phase))}
+	if event.Plugin != "" {
+		attrs = append(attrs, slog.String("plugin", event.Plugin))
+	}
+
+	level, msg := slog.LevelInfo, ""
+	switch event.Kind {
+	case EventPhaseEnter:
+		msg = "entering phase"
+	case EventPhaseExit:
+		msg = "completed phase"
+		attrs = append(attrs, slog.Duration("duration", event.Duration))
+	case EventPlugin:
+		level, msg = slog.LevelDebug, "plugin completed phase"
+		if event.Err != nil {
+			msg = "plugin failed"
+		}
+		attrs = append(attrs, slog.Duration("duration", event.Duration))
+	case EventWarning:
+		level, msg = slog.LevelWarn, "warning"
+	}
+
+	if event.Err != nil {
+		if level < slog.LevelWarn {
+			level = slog.LevelError
+		}
+		attrs = append(attrs, slog.Any("error", event.Err))
+	}
+
+	app.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+// exitProcess is the default terminator. It logs the provided error and exits the process using the error's exit code.
+func (app *Application) exitProcess(err error) {
+	if err == nil {
+		return
+	}
+
+	app.Logger().Error("application terminated", slog.Any("error", err))
+	os.Exit(ExitCode(err))
+}
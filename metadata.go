@@ -0,0 +1,90 @@
+package lifecycle
+
+import (
+	"context"
+	"path"
+	"runtime/debug"
+)
+
+// Metadata describes the application, such as its name and the version being run.
+type Metadata struct {
+	// Name is the name of the application.
+	Name string `json:"name"`
+	// Version is the version of the application.
+	Version string `json:"version"`
+	// Values holds additional metadata, such as the revision the application was built from.
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// clone returns a copy of the metadata that can be modified independently.
+func (m Metadata) clone() Metadata {
+	values := make(map[string]string, len(m.Values))
+	for key, value := range m.Values {
+		values[key] = value
+	}
+
+	m.Values = values
+	return m
+}
+
+type metadataKey struct{}
+
+func (metadataKey) String() string {
+	return "lifecycle.metadata"
+}
+
+// buildSettings are the settings recorded in the build info that are included in the metadata of the application.
+var buildSettings = []string{"vcs.revision", "vcs.time", "vcs.modified"}
+
+// defaultMetadata fills in the metadata that was not configured using the build info embedded in the binary (see
+// debug.ReadBuildInfo). The name defaults to the last element of the main package path and the version to the version
+// of the main module. The Go version and the revision the binary was built from are included in the values.
+func defaultMetadata(metadata Metadata) Metadata {
+	metadata = metadata.clone()
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return metadata
+	}
+
+	if metadata.Name == "" && info.Path != "" {
+		metadata.Name = path.Base(info.Path)
+	}
+
+	if metadata.Version == "" {
+		metadata.Version = info.Main.Version
+	}
+
+	setDefault(metadata.Values, "go.version", info.GoVersion)
+	for _, setting := range info.Settings {
+		for _, key := range buildSettings {
+			if setting.Key == key {
+				setDefault(metadata.Values, key, setting.Value)
+			}
+		}
+	}
+	return metadata
+}
+
+func setDefault(values map[string]string, key, value string) {
+	if _, ok := values[key]; !ok && value != "" {
+		values[key] = value
+	}
+}
+
+// Metadata returns the metadata describing the application. Metadata not configured using WithName, WithVersion, or
+// WithMetadata is populated using the build info embedded in the binary.
+func (app *Application) Metadata() Metadata {
+	app.on.Do(app.init)
+	return app.metadata.clone()
+}
+
+// MetadataFromContext returns the metadata of the application the provided context was derived from (such as the
+// context provided to plugins).
+func MetadataFromContext(ctx context.Context) (Metadata, bool) {
+	metadata, ok := ctx.Value(metadataKey{}).(Metadata)
+	if !ok {
+		return Metadata{}, false
+	}
+	return metadata.clone(), true
+}
@@ -0,0 +1,108 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+)
+
+// Invoker invokes a single phase of a plugin.
+type Invoker func(ctx context.Context, app *Application) error
+
+// Middleware decorates the invocation of a plugin's phase, similar to HTTP middleware. Middleware can add logging,
+// timing, retries, or panic recovery around any plugin uniformly. The provided invoker must be called to continue the
+// chain.
+type Middleware func(phase Phase, next Invoker) Invoker
+
+// Wrap decorates the provided plugin using the provided middleware. The first middleware is the outermost, observing
+// the invocation before any of the others.
+func Wrap(plugin Plugin, middleware ...Middleware) Plugin {
+	return &middlewarePlugin{
+		pluginWrapper: pluginWrapper{plugin},
+		middleware:    middleware,
+	}
+}
+
+type middlewarePlugin struct {
+	pluginWrapper
+	middleware []Middleware
+}
+
+func (p *middlewarePlugin) chain(phase Phase, invoke Invoker) Invoker {
+	for i := len(p.middleware); i > 0; i-- {
+		invoke = p.middleware[i-1](phase, invoke)
+	}
+	return invoke
+}
+
+func (p *middlewarePlugin) InitializeContext(ctx context.Context, app *Application) error {
+	return p.chain(PhaseInitialize, p.pluginWrapper.InitializeContext)(ctx, app)
+}
+
+func (p *middlewarePlugin) RunContext(ctx context.Context, app *Application) error {
+	return p.chain(PhaseRun, p.pluginWrapper.RunContext)(ctx, app)
+}
+
+func (p *middlewarePlugin) StartContext(ctx context.Context, app *Application) error {
+	return p.chain(PhaseStart, p.pluginWrapper.StartContext)(ctx, app)
+}
+
+func (p *middlewarePlugin) ShutdownContext(ctx context.Context, app *Application) error {
+	return p.chain(PhaseShutdown, p.pluginWrapper.ShutdownContext)(ctx, app)
+}
+
+var _ PluginContext = &middlewarePlugin{}
+
+// Recover is middleware that converts panics raised by a plugin into errors, allowing the application to shutdown
+// gracefully.
+func Recover(phase Phase, next Invoker) Invoker {
+	return func(ctx context.Context, app *Application) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("recovered from panic during %s: %v", phase, r)
+			}
+		}()
+
+		return next(ctx, app)
+	}
+}
+
+var _ Middleware = Recover
+
+// Interceptor decorates every plugin phase invoked by the application, provided a description of the plugin being
+// invoked. Interceptors enable cross-cutting concerns (such as tracing spans and audit logging) without wrapping each
+// plugin individually. The provided invoker must be called to continue the chain.
+type Interceptor func(plugin string, phase Phase, next Invoker) Invoker
+
+// AddInterceptor registers an interceptor that's invoked around every subsequent plugin phase. Interceptors are invoked
+// in the order they were registered, the first being the outermost.
+func (app *Application) AddInterceptor(interceptor Interceptor) {
+	app.on.Do(app.init)
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.interceptors = append(app.interceptors, owned[Interceptor]{value: interceptor, by: app.owner()})
+}
+
+// intercept decorates the provided phase of the registered plugin using the configured interceptors.
+func (app *Application) intercept(phase Phase, reg *registration, fn pluginFunc) pluginFunc {
+	app.mu.RLock()
+	interceptors := valuesOf(app.interceptors)
+	app.mu.RUnlock()
+
+	if len(interceptors) == 0 {
+		return fn
+	}
+
+	return func(ctx context.Context, app *Application, plugin Plugin) error {
+		invoke := Invoker(func(ctx context.Context, app *Application) error {
+			return fn(ctx, app, plugin)
+		})
+
+		for i := len(interceptors); i > 0; i-- {
+			invoke = interceptors[i-1](reg.String(), phase, invoke)
+		}
+
+		return invoke(ctx, app)
+	}
+}
@@ -0,0 +1,234 @@
+package lifecycle
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Option configures an Application during construction. Options are provided to NewApplication and applied prior to
+// the application being initialized.
+type Option func(app *Application)
+
+// WithHook configures a listener used to log semi-fatal errors encountered during state transitions. The option may be
+// provided multiple times to configure several hooks.
+func WithHook(hook Hook) Option {
+	return func(app *Application) {
+		app.hooks = append(app.hooks, owned[Hook]{value: hook})
+	}
+}
+
+// WithInterceptor configures an interceptor that's invoked around every plugin phase. The option may be provided
+// multiple times to configure several interceptors, the first being the outermost.
+func WithInterceptor(interceptor Interceptor) Option {
+	return func(app *Application) {
+		app.interceptors = append(app.interceptors, owned[Interceptor]{value: interceptor})
+	}
+}
+
+// WithLogger configures the logger used by the application (see Logger). Once configured, phase transitions and plugin
+// errors are logged through it, as is the error that caused the application to terminate.
+func WithLogger(logger *slog.Logger) Option {
+	return func(app *Application) {
+		app.logger = logger
+	}
+}
+
+// WithName configures the name of the application (see Metadata).
+func WithName(name string) Option {
+	return func(app *Application) {
+		app.metadata.Name = name
+	}
+}
+
+// WithVersion configures the version of the application (see Metadata).
+func WithVersion(version string) Option {
+	return func(app *Application) {
+		app.metadata.Version = version
+	}
+}
+
+// WithMetadata configures an additional value describing the application (see Metadata). The option may be provided
+// multiple times to configure several values.
+func WithMetadata(key, value string) Option {
+	return func(app *Application) {
+		if app.metadata.Values == nil {
+			app.metadata.Values = make(map[string]string)
+		}
+		app.metadata.Values[key] = value
+	}
+}
+
+// WithProgress configures a reporter that receives the progress of the application as each plugin is initialized, run,
+// started, and shutdown. The option may be provided multiple times to configure several reporters.
+func WithProgress(reporter ProgressReporter) Option {
+	return func(app *Application) {
+		app.reporters = append(app.reporters, reporter)
+	}
+}
+
+// WithHistorySize configures the number of records retained in the history of the application (see History). A size of
+// zero or less disables the history. By default, the last 256 records are retained.
+func WithHistorySize(size int) Option {
+	return func(app *Application) {
+		app.historySize = size
+		if size <= 0 {
+			app.historySize = -1
+		}
+	}
+}
+
+// WithTimingSummary logs how long each plugin took to startup (see Timings), slowest first, once the application has
+// been initialized and started. This allows developers to see which plugins make the application slow to boot.
+func WithTimingSummary() Option {
+	return func(app *Application) {
+		app.timingSummary = true
+	}
+}
+
+// WithTerminator configures the function invoked with the error that caused the application to terminate once Run or
+// Start complete. By default, the application logs the error and exits the process using the code provided by ExitCode.
+// Companies can use this to route terminal errors to their own exit and reporting logic.
+func WithTerminator(term func(err error)) Option {
+	return func(app *Application) {
+		app.term = term
+	}
+}
+
+// WithSignals configures the set of signals that trigger the shutdown of the application. By default, the application
+// is shutdown when it receives either a SIGTERM or SIGINT (os.Interrupt). On Windows, console close, logoff, and
+// shutdown events are delivered as SIGTERM.
+func WithSignals(sigs ...os.Signal) Option {
+	return func(app *Application) {
+		app.signals = sigs
+	}
+}
+
+// WithoutSignalHandling disables OS signal handling entirely. This is useful when embedding an Application within
+// another framework that already owns signal handling. Once disabled, the application is only shutdown
+// programmatically using Shutdown, or as the result of a plugin error.
+func WithoutSignalHandling() Option {
+	return func(app *Application) {
+		app.signals = []os.Signal{}
+	}
+}
+
+// WithShutdownTimeout bounds the amount of time plugins collectively have to shutdown. Should plugins exceed the
+// deadline, the application is forcefully terminated with a TimeoutError reporting which plugins were still
+// running. Plugins implementing PluginContext receive the deadline through the provided context. By default, plugins
+// have an unbounded amount of time to shutdown.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(app *Application) {
+		app.shutdownTimeout = timeout
+	}
+}
+
+// WithPreShutdownDelay delays shutting down the plugins once the application begins shutting down. The application
+// reports that it is terminating (see Terminating) during the delay, so readiness checks fail while plugins continue to
+// serve requests and work is still admitted (see Track). The application transitions to StateShutdown once the delay
+// has elapsed. This covers the window in which load balancers (such as Kubernetes endpoints) have yet to observe
+// the instance terminating and continue sending it requests. With WithForceQuit, a repeated signal forces the
+// application to quit during the delay. By default, plugins are shutdown immediately.
+func WithPreShutdownDelay(delay time.Duration) Option {
+	return func(app *Application) {
+		app.preShutdownDelay = delay
+	}
+}
+
+// WithShutdownWatchdog configures a watchdog that fires should shutting down the application take longer than the
+// watchdog's threshold. When fired, a SlowError naming the plugins still shutting down is reported to the configured
+// hooks as an EventWarning and the application is dumped (see Dump). The watchdog optionally exits the process.
+func WithShutdownWatchdog(watchdog Watchdog) Option {
+	return func(app *Application) {
+		app.shutdownWatchdog = watchdog
+	}
+}
+
+// WithStartupWatchdog reports a SlowError to the configured hooks as an EventWarning should a plugin take longer than
+// the provided threshold to initialize or start. Unlike WithInitializeTimeout and WithStartTimeout, the application
+// continues waiting on the plugin. This makes slow external dependencies visible while the application boots.
+func WithStartupWatchdog(threshold time.Duration) Option {
+	return func(app *Application) {
+		app.startupThreshold = threshold
+	}
+}
+
+// WithUpgradeTimeout bounds the amount of time the process started by Upgrade has to become ready. Should the timeout
+// be exceeded, the process is killed and the application continues running. By default, the process is given one
+// minute.
+func WithUpgradeTimeout(timeout time.Duration) Option {
+	return func(app *Application) {
+		app.upgradeTimeout = timeout
+	}
+}
+
+// WithWorkTimeout bounds the amount of time the application waits for the work in-flight (see Track) to complete when
+// shutdown, after which the remaining plugins are shutdown regardless. By default, the application waits until the work
+// completes or the shutdown timeout is exceeded.
+func WithWorkTimeout(timeout time.Duration) Option {
+	return func(app *Application) {
+		app.workTimeout = timeout
+	}
+}
+
+// WithInitializeTimeout bounds the amount of time each plugin has to initialize. Should a plugin exceed the deadline,
+// the application is shutdown with a TimeoutError naming the stuck plugin. By default, plugins have an unbounded amount
+// of time to initialize.
+func WithInitializeTimeout(timeout time.Duration) Option {
+	return func(app *Application) {
+		app.initializeTimeout = timeout
+	}
+}
+
+// WithStartTimeout bounds the amount of time each plugin has to start. Should a plugin exceed the deadline, the
+// application is shutdown with a TimeoutError naming the stuck plugin. The timeout also bounds the amount of time the
+// application waits for started plugins to be ready (see Readiness). By default, plugins have an unbounded amount of
+// time to start.
+func WithStartTimeout(timeout time.Duration) Option {
+	return func(app *Application) {
+		app.startTimeout = timeout
+	}
+}
+
+// WithContext configures the parent context the application's context is derived from. This allows callers to provide
+// a context carrying values (such as a tenant ID) or a deadline. Should the parent context be canceled, the application
+// is gracefully shutdown, just as if it had received a signal. By default, the application derives its context from
+// context.Background.
+func WithContext(ctx context.Context) Option {
+	return func(app *Application) {
+		app.parent = ctx
+	}
+}
+
+// WithForceQuit enables escalating a repeated signal into an immediate exit. After the first signal begins a graceful
+// shutdown, a second signal aborts the remaining plugin shutdowns and terminates the application with an error wrapping
+// ErrForcedShutdown that exits using the provided code. When the shutdown was not triggered by a signal (such as when
+// using Shutdown, or when the parent context is canceled), the first signal received continues the shutdown gracefully
+// and the second forces the application to quit. For example, WithForceQuit(130) mirrors the conventional exit code
+// for processes interrupted by SIGINT.
+func WithForceQuit(exitCode int) Option {
+	return func(app *Application) {
+		app.forceExitCode = exitCode
+	}
+}
+
+// WithReloadSignals configures the set of signals that trigger a reload of the application (for example, SIGHUP). Upon
+// receiving one of these signals, each plugin implementing Reloader is reloaded without tearing down the application.
+// By default, the application does not listen for reload signals.
+func WithReloadSignals(sigs ...os.Signal) Option {
+	return func(app *Application) {
+		app.reloadSignals = sigs
+	}
+}
+
+// WithParallelism bounds the number of plugins that are initialized, started, or shutdown concurrently. Plugins are
+// initialized once the plugins they depend on (see Dependent) have been initialized, so plugins that do not declare
+// their dependencies are assumed to be independent. The same applies when starting plugins, while plugins are shutdown
+// once the plugins depending on them have been shutdown. Hooks must be safe to call concurrently when parallelism is
+// enabled. By default, plugins are initialized, started, and shutdown one at a time.
+func WithParallelism(limit int) Option {
+	return func(app *Application) {
+		app.parallelism = limit
+	}
+}
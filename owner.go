@@ -0,0 +1,123 @@
+package lifecycle
+
+import (
+	"time"
+)
+
+// ownership identifies the plugin phase that made a registration with the application (such as adding a hook or
+// deferring a function). Registrations made by a plugin are released once the plugin is shutdown without the
+// application terminating (see Restart), since the plugin makes them again when it is initialized or started.
+type ownership struct {
+	reg   *registration
+	phase Phase
+}
+
+// within returns true when the registration was made by one of the provided plugins during one of the provided phases.
+func (o ownership) within(plugins []*registration, phases []Phase) bool {
+	if o.reg == nil {
+		return false
+	}
+
+	for _, phase := range phases {
+		if o.phase != phase {
+			continue
+		}
+
+		for _, reg := range plugins {
+			if o.reg == reg {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// owned associates a value registered with the application with the plugin phase that registered it.
+type owned[T any] struct {
+	value T
+	by    ownership
+}
+
+// valuesOf returns the values of the provided registrations.
+func valuesOf[T any](registrations []owned[T]) []T {
+	values := make([]T, len(registrations))
+	for i, r := range registrations {
+		values[i] = r.value
+	}
+	return values
+}
+
+// disown splits the provided registrations into those kept and those made by the provided plugins during the provided
+// phases.
+func disown[T any](registrations []owned[T], plugins []*registration, phases []Phase) ([]owned[T], []T) {
+	kept := make([]owned[T], 0, len(registrations))
+	released := make([]T, 0)
+
+	for _, r := range registrations {
+		if r.by.within(plugins, phases) {
+			released = append(released, r.value)
+		} else {
+			kept = append(kept, r)
+		}
+	}
+	return kept, released
+}
+
+// owner returns the plugin phase in progress, which registrations made with the application are attributed to.
+// Registrations made while no plugin is being initialized or started belong to the application. When several plugins
+// are initialized or started concurrently (see WithParallelism), registrations are attributed to one of them, since
+// such plugins are released together. Callers must hold the lock.
+func (app *Application) owner() ownership {
+	for reg, invocation := range app.inflight {
+		if invocation.phase == PhaseInitialize || invocation.phase == PhaseStart {
+			return ownership{reg: reg, phase: invocation.phase}
+		}
+	}
+	return ownership{}
+}
+
+// ownedHooks returns the functions registered using OnShutdown by the provided plugins during the provided phases.
+func (app *Application) ownedHooks(plugins []*registration, phases ...Phase) []*registration {
+	hooks := make([]*registration, 0)
+	for _, reg := range app.registered() {
+		if reg.hook() && reg.plugin.(*shutdownHook).by.within(plugins, phases) {
+			hooks = append(hooks, reg)
+		}
+	}
+	return hooks
+}
+
+// release drops the hooks, interceptors, state listeners, signal handlers, and functions registered using OnShutdown by
+// the provided plugins during the provided phases. The functions they deferred (including closing the listeners they
+// opened using Listen) are invoked in the reverse order they were registered, and failures are reported to the
+// configured hooks as an EventWarning.
+func (app *Application) release(plugins []*registration, phases ...Phase) {
+	app.mu.Lock()
+
+	var deferred []func() error
+	app.hooks, _ = disown(app.hooks, plugins, phases)
+	app.interceptors, _ = disown(app.interceptors, plugins, phases)
+	app.listeners, _ = disown(app.listeners, plugins, phases)
+	app.deferred, deferred = disown(app.deferred, plugins, phases)
+
+	for sig, handlers := range app.handlers {
+		app.handlers[sig], _ = disown(handlers, plugins, phases)
+	}
+
+	// registrations are replaced rather than modified, since snapshots share the underlying array (see registered)
+	kept := make([]*registration, 0, len(app.plugins))
+	for _, reg := range app.plugins {
+		if !reg.hook() || !reg.plugin.(*shutdownHook).by.within(plugins, phases) {
+			kept = append(kept, reg)
+		}
+	}
+	app.plugins = kept
+
+	app.mu.Unlock()
+
+	for i := len(deferred); i > 0; i-- {
+		if err := deferred[i-1](); err != nil {
+			app.report(newEvent(EventWarning, "", "", time.Now(), err))
+		}
+	}
+}
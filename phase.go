@@ -0,0 +1,151 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+)
+
+// PhasePosition positions a custom phase relative to a built-in phase.
+type PhasePosition int
+
+const (
+	// Before positions a custom phase before a built-in phase.
+	Before PhasePosition = iota
+	// After positions a custom phase after a built-in phase.
+	After
+)
+
+func (p PhasePosition) String() string {
+	if p == After {
+		return "after"
+	}
+	return "before"
+}
+
+// PhaseHandler is an optional interface plugins can implement to participate in custom phases (see WithPhase). Plugins
+// are provided the phase being invoked and should return nil for phases they do not participate in.
+type PhaseHandler interface {
+	HandlePhase(ctx context.Context, app *Application, phase Phase) error
+}
+
+// customPhase is a user-defined phase positioned relative to a built-in phase.
+type customPhase struct {
+	phase    Phase
+	position PhasePosition
+	anchor   Phase
+}
+
+// anchors are the positions custom phases can occupy.
+var anchors = map[PhasePosition][]Phase{
+	Before: {PhaseRun, PhaseStart, PhaseShutdown},
+	After:  {PhaseInitialize, PhaseRun, PhaseStart},
+}
+
+// WithPhase registers a custom phase (such as "migrate", "warmup", or "flush") positioned relative to a built-in
+// phase. Plugins implementing PhaseHandler are invoked in the order they were registered, except for phases positioned
+// before PhaseShutdown which are invoked in the reverse order. Custom phases can be positioned after PhaseInitialize
+// (before either Run or Start), before or after PhaseRun and PhaseStart, and before PhaseShutdown. Should a plugin fail
+// during a custom phase, the application is shutdown. Errors encountered before PhaseShutdown are reported alongside
+// other shutdown errors. WithPhase panics when provided an unsupported position.
+func WithPhase(phase Phase, position PhasePosition, anchor Phase) Option {
+	supported := false
+	for _, candidate := range anchors[position] {
+		supported = supported || candidate == anchor
+	}
+
+	if !supported {
+		panic(fmt.Sprintf("lifecycle: cannot add phase %s %s %s", phase, position, anchor))
+	}
+
+	return func(app *Application) {
+		app.phases = append(app.phases, customPhase{
+			phase:    phase,
+			position: position,
+			anchor:   anchor,
+		})
+	}
+}
+
+func findPhaseHandler(plugin Plugin) (PhaseHandler, bool) {
+	p, ok := findPlugin(plugin, func(p Plugin) bool {
+		_, ok := p.(PhaseHandler)
+		return ok
+	})
+	if !ok {
+		return nil, false
+	}
+	return p.(PhaseHandler), true
+}
+
+// handlePhase returns a function invoking the provided custom phase of a plugin.
+func handlePhase(phase Phase) pluginFunc {
+	return func(ctx context.Context, app *Application, plugin Plugin) error {
+		handler, ok := findPhaseHandler(plugin)
+		if !ok {
+			return nil
+		}
+		return handler.HandlePhase(ctx, app, phase)
+	}
+}
+
+// runPhases runs each custom phase at the provided position, returning the first error encountered.
+func (app *Application) runPhases(position PhasePosition, anchor Phase) error {
+	for _, custom := range app.phases {
+		if custom.position != position || custom.anchor != anchor {
+			continue
+		}
+
+		if err := app.runPhase(custom.phase); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runPhase invokes the provided custom phase of each initialized plugin, stopping at the first error.
+func (app *Application) runPhase(phase Phase) error {
+	started := app.enter(phase)
+
+	for _, reg := range app.registered() {
+		if app.isTerminating() {
+			return nil // shutdown was triggered elsewhere
+		}
+
+		if _, ok := findPhaseHandler(reg.plugin); !ok || !reg.initialized() {
+			continue
+		}
+
+		if err := app.invoke(app.Context(), phase, 0, reg, handlePhase(phase)); err != nil {
+			return err
+		}
+	}
+
+	app.exit(phase, started, nil)
+	return nil
+}
+
+// runShutdownPhases invokes the custom phases positioned before PhaseShutdown on each of the provided registrations
+// in reverse order, returning all errors encountered.
+func (app *Application) runShutdownPhases(ctx context.Context, registrations []*registration) []error {
+	errs := make([]error, 0)
+
+	for _, custom := range app.phases {
+		if custom.position != Before || custom.anchor != PhaseShutdown {
+			continue
+		}
+
+		started := app.enter(custom.phase)
+		for _, reg := range reversed(registrations) {
+			if _, ok := findPhaseHandler(reg.plugin); !ok {
+				continue
+			}
+
+			if err := app.invoke(ctx, custom.phase, 0, reg, handlePhase(custom.phase)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		app.exit(custom.phase, started, nil)
+	}
+
+	return errs
+}
@@ -1,5 +1,10 @@
 package lifecycle
 
+import (
+	"context"
+	"fmt"
+)
+
 // Plugin defines an abstraction to developers to tie into the various lifecycle events of an application. It's
 // important that plugins be written in such a way where some of their common resources may not exist.
 type Plugin interface {
@@ -56,3 +61,136 @@ func (p PluginFuncs) Shutdown(app *Application) error {
 }
 
 var _ Plugin = PluginFuncs{}
+
+// PluginContext is an optional interface that plugins can implement to receive a context.Context during each phase of
+// the lifecycle. This allows plugins to natively respect cancellation and deadlines instead of manually pulling the
+// context off the Application. When a plugin implements PluginContext, the Application invokes these methods in place
+// of the ones defined on Plugin. Plugins can embed PluginFuncs to satisfy the Plugin interface.
+type PluginContext interface {
+	// InitializeContext is the context-aware variant of Plugin.Initialize.
+	InitializeContext(ctx context.Context, app *Application) error
+	// RunContext is the context-aware variant of Plugin.Run.
+	RunContext(ctx context.Context, app *Application) error
+	// StartContext is the context-aware variant of Plugin.Start.
+	StartContext(ctx context.Context, app *Application) error
+	// ShutdownContext is the context-aware variant of Plugin.Shutdown.
+	ShutdownContext(ctx context.Context, app *Application) error
+}
+
+func initializePlugin(ctx context.Context, app *Application, plugin Plugin) error {
+	if p, ok := plugin.(PluginContext); ok {
+		return p.InitializeContext(ctx, app)
+	}
+	return plugin.Initialize(app)
+}
+
+func runPlugin(ctx context.Context, app *Application, plugin Plugin) error {
+	if p, ok := plugin.(PluginContext); ok {
+		return p.RunContext(ctx, app)
+	}
+	return plugin.Run(app)
+}
+
+func startPlugin(ctx context.Context, app *Application, plugin Plugin) error {
+	if p, ok := plugin.(PluginContext); ok {
+		return p.StartContext(ctx, app)
+	}
+	return plugin.Start(app)
+}
+
+func shutdownPlugin(ctx context.Context, app *Application, plugin Plugin) error {
+	if p, ok := plugin.(PluginContext); ok {
+		return p.ShutdownContext(ctx, app)
+	}
+	return plugin.Shutdown(app)
+}
+
+// pluginWrapper decorates a plugin while continuing to dispatch to the optional interfaces it implements.
+type pluginWrapper struct {
+	Plugin
+}
+
+func (w pluginWrapper) InitializeContext(ctx context.Context, app *Application) error {
+	return initializePlugin(ctx, app, w.Plugin)
+}
+
+func (w pluginWrapper) RunContext(ctx context.Context, app *Application) error {
+	return runPlugin(ctx, app, w.Plugin)
+}
+
+func (w pluginWrapper) StartContext(ctx context.Context, app *Application) error {
+	return startPlugin(ctx, app, w.Plugin)
+}
+
+func (w pluginWrapper) ShutdownContext(ctx context.Context, app *Application) error {
+	return shutdownPlugin(ctx, app, w.Plugin)
+}
+
+func (w pluginWrapper) unwrap() Plugin {
+	return w.Plugin
+}
+
+var _ PluginContext = pluginWrapper{}
+
+// unwrapper is implemented by plugins that decorate another plugin.
+type unwrapper interface {
+	unwrap() Plugin
+}
+
+// findPlugin walks the chain of wrapped plugins and returns the first that satisfies the provided predicate.
+func findPlugin(plugin Plugin, match func(plugin Plugin) bool) (Plugin, bool) {
+	for plugin != nil {
+		if match(plugin) {
+			return plugin, true
+		}
+
+		w, ok := plugin.(unwrapper)
+		if !ok {
+			break
+		}
+		plugin = w.unwrap()
+	}
+	return nil, false
+}
+
+// Named is an optional interface plugins can implement to provide a name. Names are included in the errors and events
+// pertaining to the plugin and can be used to retrieve the plugin from the application using Application.Plugin.
+type Named interface {
+	Name() string
+}
+
+// innermost returns the plugin decorated by any wrappers.
+func innermost(plugin Plugin) Plugin {
+	inner, _ := findPlugin(plugin, func(p Plugin) bool {
+		_, ok := p.(unwrapper)
+		return !ok
+	})
+	return inner
+}
+
+// pluginName returns the name of the plugin at the provided index. Plugins that do not implement Named are named using
+// their index.
+func pluginName(i int, plugin Plugin) string {
+	p, ok := findPlugin(plugin, func(p Plugin) bool {
+		_, ok := p.(Named)
+		return ok
+	})
+	if !ok {
+		return fmt.Sprintf("plugin[%d]", i)
+	}
+	return p.(Named).Name()
+}
+
+// describePlugin returns a human readable description of the named plugin.
+func describePlugin(name string, plugin Plugin) string {
+	return fmt.Sprintf("%s (%T)", name, innermost(plugin))
+}
+
+// describeRegistrations returns a human readable description of each of the provided registrations.
+func describeRegistrations(registrations []*registration) []string {
+	descriptions := make([]string, len(registrations))
+	for i, reg := range registrations {
+		descriptions[i] = reg.String()
+	}
+	return descriptions
+}
@@ -0,0 +1,3 @@
+// Package adminplugin provides a plugin serving lifecycle operations over a unix socket, allowing operators to inspect,
+// reload, or shutdown the application without exposing them over the network.
+package adminplugin
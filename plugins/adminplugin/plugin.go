@@ -0,0 +1,275 @@
+package adminplugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+const (
+	// DefaultMode is the file mode of the socket when no mode is configured, restricting it to the owner of the
+	// process.
+	DefaultMode os.FileMode = 0o600
+
+	// CommandStatus reports the state of the application.
+	CommandStatus = "status"
+	// CommandPlugins reports the status of each plugin (see lifecycle.Application.PluginStatuses).
+	CommandPlugins = "plugins"
+	// CommandShutdown triggers a graceful shutdown of the application.
+	CommandShutdown = "shutdown"
+	// CommandReload reloads the application (see lifecycle.Application.Reload).
+	CommandReload = "reload"
+)
+
+// Status is the response to CommandStatus.
+type Status struct {
+	lifecycle.Metadata
+	State    string `json:"state"`
+	Ready    bool   `json:"ready"`
+	InFlight int    `json:"in_flight"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Option configures the plugin.
+type Option func(p *plugin)
+
+// WithMode configures the file mode of the socket.
+func WithMode(mode os.FileMode) Option {
+	return func(p *plugin) {
+		p.mode = mode
+	}
+}
+
+// Plugin returns a plugin serving lifecycle operations on a unix socket at the provided path while the application is
+// started. Each line received is a command (CommandStatus, CommandPlugins, CommandShutdown, or CommandReload) answered
+// by a single line of JSON, with failures reported under "error". For example:
+//
+//	echo status | nc -U /run/app/admin.sock
+//
+// A stale socket left at the path by a previous process is removed during initialization, while a socket accepting
+// connections is left in place and fails the initialization.
+func Plugin(path string, opts ...Option) lifecycle.Plugin {
+	p := &plugin{
+		path: path,
+		mode: DefaultMode,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	path string
+	mode os.FileMode
+
+	listener net.Listener
+	exited   chan error
+
+	mu      sync.Mutex
+	closing bool
+	conns   map[net.Conn]struct{}
+	serving sync.WaitGroup
+}
+
+func (p *plugin) Name() string {
+	return "admin"
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	listener, err := p.listen(app)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chmod(p.path, p.mode); err != nil {
+		return err
+	}
+
+	p.listener = listener
+	p.closing = false
+	p.conns = make(map[net.Conn]struct{})
+	return nil
+}
+
+// listen listens on the socket, removing a stale socket left at the path by a previous process. The socket is not
+// removed when a listener on it was inherited (see lifecycle.Application.Upgrade), or while it accepts connections.
+func (p *plugin) listen(app *lifecycle.Application) (net.Listener, error) {
+	listener, err := app.Listen("unix", p.path)
+	if err == nil || !errors.Is(err, syscall.EADDRINUSE) {
+		return listener, err
+	}
+
+	if info, statErr := os.Lstat(p.path); statErr != nil || info.Mode()&os.ModeSocket == 0 {
+		return nil, err
+	}
+
+	conn, dialErr := net.Dial("unix", p.path)
+	if dialErr == nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("admin socket %s is in use: %w", p.path, err)
+	}
+	if !errors.Is(dialErr, syscall.ECONNREFUSED) {
+		return nil, err
+	}
+
+	if err := os.Remove(p.path); err != nil {
+		return nil, err
+	}
+	return app.Listen("unix", p.path)
+}
+
+func (p *plugin) Start(app *lifecycle.Application) error {
+	p.exited = make(chan error, 1)
+	go func() {
+		p.exited <- p.accept(app)
+	}()
+	return nil
+}
+
+func (p *plugin) Shutdown(_ *lifecycle.Application) error {
+	if p.exited == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	p.closing = true
+	for conn := range p.conns {
+		_ = conn.Close()
+	}
+	p.mu.Unlock()
+
+	err := p.listener.Close()
+	p.serving.Wait()
+
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+func (p *plugin) Exited() <-chan error {
+	return p.exited
+}
+
+var _ lifecycle.Exiter = &plugin{}
+
+// accept serves connections until the listener is closed.
+func (p *plugin) accept(app *lifecycle.Application) error {
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			p.mu.Lock()
+			closing := p.closing
+			p.mu.Unlock()
+
+			if closing || app.State() >= lifecycle.StateShutdown {
+				return nil
+			}
+			return err
+		}
+
+		p.mu.Lock()
+		if p.closing {
+			p.mu.Unlock()
+			_ = conn.Close()
+			return nil
+		}
+		p.conns[conn] = struct{}{}
+		p.serving.Add(1)
+		p.mu.Unlock()
+
+		go p.serve(app, conn)
+	}
+}
+
+// serve answers each command received on the connection until it is closed.
+func (p *plugin) serve(app *lifecycle.Application, conn net.Conn) {
+	defer p.serving.Done()
+	defer func() {
+		p.mu.Lock()
+		delete(p.conns, conn)
+		p.mu.Unlock()
+
+		_ = conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		command := strings.TrimSpace(scanner.Text())
+		if command == "" {
+			continue
+		}
+
+		if err := encoder.Encode(handle(app, command)); err != nil {
+			return
+		}
+
+		if command == CommandShutdown {
+			// the response is written before the connection is closed by the shutdown
+			app.Shutdown(nil)
+		}
+	}
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type okResponse struct {
+	OK bool `json:"ok"`
+}
+
+// handle returns the response to the provided command.
+func handle(app *lifecycle.Application, command string) interface{} {
+	switch command {
+	case CommandStatus:
+		return status(app)
+	case CommandPlugins:
+		return app.PluginStatuses()
+	case CommandShutdown:
+		if app.State() >= lifecycle.StateShutdown {
+			return errorResponse{Error: lifecycle.ErrAlreadyTerminated.Error()}
+		}
+		return okResponse{OK: true}
+	case CommandReload:
+		if err := app.Reload(); err != nil {
+			return errorResponse{Error: err.Error()}
+		}
+		return okResponse{OK: true}
+	default:
+		return errorResponse{Error: fmt.Sprintf("unknown command %q", command)}
+	}
+}
+
+// status describes the application.
+func status(app *lifecycle.Application) Status {
+	s := Status{
+		Metadata: app.Metadata(),
+		State:    app.State().String(),
+		InFlight: app.InFlight(),
+	}
+
+	select {
+	case <-app.Ready():
+		s.Ready = true
+	default:
+	}
+
+	if reason := app.ShutdownReason(); reason.Kind != lifecycle.ShutdownNone {
+		s.Reason = reason.String()
+	}
+	return s
+}
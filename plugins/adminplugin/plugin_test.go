@@ -0,0 +1,129 @@
+package adminplugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+type reloader struct {
+	lifecycle.PluginFuncs
+	reloads int
+}
+
+func (r *reloader) Reload(_ *lifecycle.Application) error {
+	r.reloads++
+	return nil
+}
+
+func Test_Plugin(t *testing.T) {
+	// unix socket paths are limited in length, so avoid the long paths of t.TempDir
+	dir, err := os.MkdirTemp("", "admin")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "admin.sock")
+	r := &reloader{}
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}), lifecycle.WithName("api"))
+	app.Initialize(Plugin(path), r)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, DefaultMode, info.Mode().Perm())
+
+	responses := make([]json.RawMessage, 0)
+	var requestErr error
+	read := make(chan struct{})
+
+	go func() {
+		defer close(read)
+		<-app.Ready()
+
+		conn, err := net.Dial("unix", path)
+		if err != nil {
+			requestErr = err
+			app.Shutdown(nil)
+			return
+		}
+		defer conn.Close()
+
+		if _, err := conn.Write([]byte("status\nplugins\nreload\nrestart\nshutdown\n")); err != nil {
+			requestErr = err
+			app.Shutdown(nil)
+			return
+		}
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			responses = append(responses, json.RawMessage(scanner.Text()))
+		}
+	}()
+
+	require.NoError(t, app.StartE())
+	<-read
+	require.NoError(t, requestErr)
+	require.Len(t, responses, 5)
+
+	var status Status
+	require.NoError(t, json.Unmarshal(responses[0], &status))
+	require.Equal(t, "api", status.Name)
+	require.Equal(t, "started", status.State)
+	require.True(t, status.Ready)
+
+	var plugins []lifecycle.PluginStatus
+	require.NoError(t, json.Unmarshal(responses[1], &plugins))
+	require.Len(t, plugins, 2)
+	require.Equal(t, "admin", plugins[0].Name)
+	require.Equal(t, "started", plugins[0].Status)
+
+	require.JSONEq(t, `{"ok": true}`, string(responses[2]))
+	require.Equal(t, 1, r.reloads)
+	require.JSONEq(t, `{"error": "unknown command \"restart\""}`, string(responses[3]))
+	require.JSONEq(t, `{"ok": true}`, string(responses[4]))
+
+	require.Equal(t, lifecycle.ShutdownRequested, app.ShutdownReason().Kind)
+
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err), "socket not removed")
+}
+
+func Test_Plugin_Socket(t *testing.T) {
+	// unix socket paths are limited in length, so avoid the long paths of t.TempDir
+	dir, err := os.MkdirTemp("", "admin")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "admin.sock")
+
+	live, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	// a socket accepting connections is left in place
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(Plugin(path))
+	require.ErrorContains(t, app.StartE(), "is in use")
+
+	_, err = os.Stat(path)
+	require.NoError(t, err)
+
+	// a stale socket is removed
+	live.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, live.Close())
+
+	app = lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(Plugin(path))
+	app.AfterStart(func(_ context.Context) error {
+		go app.Shutdown(nil)
+		return nil
+	})
+	require.NoError(t, app.StartE())
+}
@@ -0,0 +1,3 @@
+// Package autocertplugin provides a plugin obtaining and renewing certificates from Let's Encrypt (or another ACME
+// certificate authority) using the autocert package.
+package autocertplugin
@@ -0,0 +1,170 @@
+package autocertplugin
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/effxhq/go-lifecycle"
+	"github.com/effxhq/go-lifecycle/plugins/tlsplugin"
+)
+
+const (
+	// DefaultCacheDir is the directory certificates are cached in when no cache is configured.
+	DefaultCacheDir = "autocert"
+	// DefaultChallengeAddr is the address the HTTP-01 challenge is served on when no address is configured.
+	DefaultChallengeAddr = ":80"
+)
+
+type contextKey struct{}
+
+func (contextKey) String() string {
+	return "autocert manager"
+}
+
+// Option configures the plugin.
+type Option func(p *plugin)
+
+// WithCache configures where certificates are cached. Without a cache, certificates are requested each time the
+// application starts, quickly exceeding the rate limits of Let's Encrypt.
+func WithCache(cache autocert.Cache) Option {
+	return func(p *plugin) {
+		p.manager.Cache = cache
+	}
+}
+
+// WithEmail configures the contact address provided to the certificate authority.
+func WithEmail(email string) Option {
+	return func(p *plugin) {
+		p.manager.Email = email
+	}
+}
+
+// WithChallengeAddr configures the address the HTTP-01 challenge is served on.
+func WithChallengeAddr(addr string) Option {
+	return func(p *plugin) {
+		p.challengeAddr = addr
+	}
+}
+
+// WithManager customizes the manager (such as the ACME client used to reach a staging environment) before it is used.
+func WithManager(configure func(manager *autocert.Manager)) Option {
+	return func(p *plugin) {
+		p.configure = append(p.configure, configure)
+	}
+}
+
+// Plugin returns a plugin obtaining certificates for the provided hosts, accepting the terms of service of the
+// certificate authority. During initialization, a tls.Config serving the certificates is shared with the server
+// plugins (see tlsplugin.FromContext), and the manager is attached to the application context (see FromContext). The
+// HTTP-01 challenge is served while the plugin is started, redirecting all other requests to HTTPS. Certificates are
+// cached in DefaultCacheDir unless configured otherwise.
+func Plugin(hosts []string, opts ...Option) lifecycle.Plugin {
+	p := &plugin{
+		manager: &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(hosts...),
+			Cache:      autocert.DirCache(DefaultCacheDir),
+		},
+		challengeAddr: DefaultChallengeAddr,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	manager       *autocert.Manager
+	challengeAddr string
+	configure     []func(manager *autocert.Manager)
+
+	listener net.Listener
+	server   *http.Server
+	exited   chan error
+}
+
+// Name returns tlsplugin.Name, allowing server plugins configured to serve TLS to depend on the plugin.
+func (p *plugin) Name() string {
+	return tlsplugin.Name
+}
+
+func (p *plugin) InitializeContext(_ context.Context, app *lifecycle.Application) error {
+	for _, configure := range p.configure {
+		configure(p.manager)
+	}
+	p.configure = nil
+
+	listener, err := app.Listen("tcp", p.challengeAddr)
+	if err != nil {
+		return err
+	}
+
+	p.listener = listener
+	p.server = &http.Server{
+		Handler:           p.manager.HTTPHandler(nil),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	config := p.manager.TLSConfig()
+	config.MinVersion = tls.VersionTLS12
+
+	app.WithValue(contextKey{}, p.manager)
+	tlsplugin.Provide(app, config)
+	return nil
+}
+
+func (p *plugin) RunContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) StartContext(_ context.Context, _ *lifecycle.Application) error {
+	p.exited = make(chan error, 1)
+	go func() {
+		err := p.server.Serve(p.listener)
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+		p.exited <- err
+	}()
+	return nil
+}
+
+func (p *plugin) ShutdownContext(ctx context.Context, _ *lifecycle.Application) error {
+	if p.exited == nil {
+		return nil
+	}
+	return p.server.Shutdown(ctx)
+}
+
+func (p *plugin) Exited() <-chan error {
+	return p.exited
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	return p.InitializeContext(app.Context(), app)
+}
+
+func (p *plugin) Start(app *lifecycle.Application) error {
+	return p.StartContext(app.Context(), app)
+}
+
+func (p *plugin) Shutdown(app *lifecycle.Application) error {
+	return p.ShutdownContext(app.Context(), app)
+}
+
+var _ lifecycle.PluginContext = &plugin{}
+var _ lifecycle.Exiter = &plugin{}
+
+// FromContext returns the manager attached to the provided context.
+func FromContext(ctx context.Context) (*autocert.Manager, bool) {
+	manager, ok := ctx.Value(contextKey{}).(*autocert.Manager)
+	return manager, ok
+}
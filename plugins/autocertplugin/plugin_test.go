@@ -0,0 +1,70 @@
+package autocertplugin
+
+import (
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/effxhq/go-lifecycle"
+	"github.com/effxhq/go-lifecycle/plugins/tlsplugin"
+)
+
+func Test_Plugin(t *testing.T) {
+	// reserve a free port to serve the challenge on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	var location string
+	var requestErr error
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(Plugin(
+		[]string{"example.com"},
+		WithCache(autocert.DirCache(t.TempDir())),
+		WithChallengeAddr(addr),
+	))
+
+	manager, ok := FromContext(app.Context())
+	require.True(t, ok, "manager not attached")
+	require.NoError(t, manager.HostPolicy(nil, "example.com"))
+
+	config, ok := tlsplugin.FromContext(app.Context())
+	require.True(t, ok, "config not shared")
+	require.Contains(t, config.NextProtos, "acme-tls/1")
+
+	go func() {
+		<-app.Ready()
+		defer app.Shutdown(nil)
+
+		client := &http.Client{
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+
+		req, err := http.NewRequest(http.MethodGet, "http://"+addr+"/index.html", nil)
+		if err != nil {
+			requestErr = err
+			return
+		}
+		req.Host = "example.com"
+
+		resp, err := client.Do(req)
+		if err != nil {
+			requestErr = err
+			return
+		}
+		defer resp.Body.Close()
+
+		location = resp.Header.Get("Location")
+	}()
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, requestErr)
+	require.Equal(t, "https://example.com/index.html", location)
+}
@@ -0,0 +1,2 @@
+// Package cmuxplugin provides a plugin serving gRPC and HTTP on a single port by multiplexing connections using cmux.
+package cmuxplugin
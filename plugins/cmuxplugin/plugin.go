@@ -0,0 +1,285 @@
+package cmuxplugin
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/soheilhy/cmux"
+	"google.golang.org/grpc"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+const (
+	// DefaultName is the name of the plugin when no name is configured.
+	DefaultName = "cmux"
+	// DefaultDrainTimeout is how long in-flight requests and RPCs are given to complete during shutdown when no drain
+	// timeout is configured.
+	DefaultDrainTimeout = 30 * time.Second
+	// DefaultReadHeaderTimeout bounds how long the HTTP server waits on the headers of a request.
+	DefaultReadHeaderTimeout = 10 * time.Second
+)
+
+type contextKey struct {
+	name     string
+	protocol string
+}
+
+func (k contextKey) String() string {
+	return "cmux " + k.protocol + " server " + k.name
+}
+
+// Option configures the plugin.
+type Option func(p *plugin)
+
+// WithName configures the name of the plugin, allowing several multiplexed ports to be registered with the same
+// application. The servers are attached to the application context under this name (see GRPCServer and HTTPServer).
+func WithName(name string) Option {
+	return func(p *plugin) {
+		p.name = name
+	}
+}
+
+// WithDrainTimeout configures how long in-flight requests and RPCs are given to complete during shutdown. Once
+// exceeded, the remaining connections are closed.
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(p *plugin) {
+		p.drainTimeout = timeout
+	}
+}
+
+// WithHandler configures the handler serving HTTP requests. By default, HTTP requests are answered with 404 Not Found.
+func WithHandler(handler http.Handler) Option {
+	return func(p *plugin) {
+		p.handler = handler
+	}
+}
+
+// WithServerOptions configures the options the gRPC server is constructed with (such as interceptors).
+func WithServerOptions(opts ...grpc.ServerOption) Option {
+	return func(p *plugin) {
+		p.serverOpts = append(p.serverOpts, opts...)
+	}
+}
+
+// WithDebug additionally serves the pprof endpoints under /debug/pprof/ and the expvars under /debug/vars over HTTP.
+func WithDebug() Option {
+	return func(p *plugin) {
+		p.debug = true
+	}
+}
+
+// New returns a plugin serving gRPC and HTTP on the provided address. Connections are matched to gRPC by their
+// content-type, and all other connections are served over HTTP. The servers are constructed during initialization and
+// attached to the application context (see GRPCServer and HTTPServer), allowing the plugins following it to register
+// their services. The address is listened on during initialization (see Application.Listen), and the servers begin
+// accepting connections when the plugin is started. When the application is shutdown, the plugin is drained (see
+// lifecycle.Drainer) before any plugin is shutdown: the port stops accepting connections before both servers are
+// stopped gracefully, and the remaining connections are closed once the drain timeout (see WithDrainTimeout) is
+// exceeded.
+func New(addr string, opts ...Option) lifecycle.Plugin {
+	p := &plugin{
+		name:         DefaultName,
+		addr:         addr,
+		handler:      http.NotFoundHandler(),
+		drainTimeout: DefaultDrainTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	name         string
+	addr         string
+	handler      http.Handler
+	drainTimeout time.Duration
+	serverOpts   []grpc.ServerOption
+	debug        bool
+
+	listener   net.Listener
+	mux        cmux.CMux
+	grpcServer *grpc.Server
+	httpServer *http.Server
+	grpcL      net.Listener
+	httpL      net.Listener
+
+	draining atomic.Bool
+	once     *sync.Once
+	exited   chan error
+}
+
+func (p *plugin) Name() string {
+	return p.name
+}
+
+func (p *plugin) InitializeContext(_ context.Context, app *lifecycle.Application) error {
+	listener, err := app.Listen("tcp", p.addr)
+	if err != nil {
+		return err
+	}
+
+	p.listener = listener
+	p.mux = cmux.New(listener)
+
+	// the multiplexed listeners close the port when closed, which is left to the plugin so it is closed once
+	grpcMatcher := cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc")
+	p.grpcL = unclosable{p.mux.MatchWithWriters(grpcMatcher)}
+	p.httpL = unclosable{p.mux.Match(cmux.Any())}
+
+	handler := p.handler
+	if p.debug {
+		handler = debugHandler(handler)
+	}
+
+	p.grpcServer = grpc.NewServer(p.serverOpts...)
+	p.httpServer = &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: DefaultReadHeaderTimeout,
+		BaseContext: func(net.Listener) context.Context {
+			return app.Context()
+		},
+	}
+	p.draining.Store(false)
+	p.exited = nil
+
+	app.WithValue(contextKey{p.name, "grpc"}, p.grpcServer)
+	app.WithValue(contextKey{p.name, "http"}, p.httpServer)
+	return nil
+}
+
+func (p *plugin) RunContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) StartContext(_ context.Context, _ *lifecycle.Application) error {
+	p.once = &sync.Once{}
+	p.exited = make(chan error, 1)
+
+	go p.serve(func() error { return p.grpcServer.Serve(p.grpcL) })
+	go p.serve(func() error { return p.httpServer.Serve(p.httpL) })
+	go p.serve(p.mux.Serve)
+	return nil
+}
+
+// serve reports the result of the first server to exit. Errors are expected once the plugin is draining, since the
+// servers exit as the port is closed.
+func (p *plugin) serve(fn func() error) {
+	err := fn()
+	if p.draining.Load() {
+		err = nil
+	}
+
+	p.once.Do(func() {
+		p.exited <- err
+	})
+}
+
+// Drain closes the port before stopping both servers gracefully, closing the remaining connections once the drain
+// timeout is exceeded.
+func (p *plugin) Drain(ctx context.Context, _ *lifecycle.Application) error {
+	if p.exited == nil || p.draining.Swap(true) {
+		return nil
+	}
+
+	if p.drainTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.drainTimeout)
+		defer cancel()
+	}
+
+	_ = p.listener.Close()
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		p.grpcServer.GracefulStop()
+	}()
+
+	errs := make([]error, 0, 2)
+	if err := p.httpServer.Shutdown(ctx); err != nil {
+		errs = append(errs, err, p.httpServer.Close())
+	}
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		p.grpcServer.Stop()
+		<-stopped
+		errs = append(errs, fmt.Errorf("grpc server stopped forcefully: %w", ctx.Err()))
+	}
+
+	return errors.Join(errs...)
+}
+
+// ShutdownContext drains the plugin when it has yet to be drained (for example, when the plugin is restarted).
+func (p *plugin) ShutdownContext(ctx context.Context, app *lifecycle.Application) error {
+	return p.Drain(ctx, app)
+}
+
+func (p *plugin) Exited() <-chan error {
+	return p.exited
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	return p.InitializeContext(app.Context(), app)
+}
+
+func (p *plugin) Start(app *lifecycle.Application) error {
+	return p.StartContext(app.Context(), app)
+}
+
+func (p *plugin) Shutdown(app *lifecycle.Application) error {
+	return p.ShutdownContext(app.Context(), app)
+}
+
+var _ lifecycle.PluginContext = &plugin{}
+var _ lifecycle.Exiter = &plugin{}
+var _ lifecycle.Drainer = &plugin{}
+
+// unclosable prevents a server from closing the port shared by the multiplexed listeners.
+type unclosable struct {
+	net.Listener
+}
+
+func (unclosable) Close() error {
+	return nil
+}
+
+// debugHandler serves the pprof endpoints and expvars, falling back to the provided handler.
+func debugHandler(handler http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.Handle("/", handler)
+	return mux
+}
+
+// GRPCServer returns the gRPC server attached to the provided context by the plugin with the provided name
+// (DefaultName unless configured using WithName).
+func GRPCServer(ctx context.Context, name string) (*grpc.Server, bool) {
+	server, ok := ctx.Value(contextKey{name, "grpc"}).(*grpc.Server)
+	return server, ok
+}
+
+// HTTPServer returns the HTTP server attached to the provided context by the plugin with the provided name
+// (DefaultName unless configured using WithName).
+func HTTPServer(ctx context.Context, name string) (*http.Server, bool) {
+	server, ok := ctx.Value(contextKey{name, "http"}).(*http.Server)
+	return server, ok
+}
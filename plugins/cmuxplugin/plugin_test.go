@@ -0,0 +1,102 @@
+package cmuxplugin
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+func Test_Plugin(t *testing.T) {
+	// reserve a free port for the plugin to listen on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "hello")
+	})
+
+	var status grpc_health_v1.HealthCheckResponse_ServingStatus
+	var body string
+	var debugStatus int
+	var requestErr error
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(
+		New(addr, WithHandler(handler), WithDebug()),
+		&lifecycle.PluginFuncs{
+			InitializeFunc: func(app *lifecycle.Application) error {
+				server, ok := GRPCServer(app.Context(), DefaultName)
+				require.True(t, ok, "grpc server not attached")
+
+				_, ok = HTTPServer(app.Context(), DefaultName)
+				require.True(t, ok, "http server not attached")
+
+				grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+				return nil
+			},
+		},
+	)
+
+	go func() {
+		<-app.Ready()
+		defer app.Shutdown(nil)
+
+		conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			requestErr = err
+			return
+		}
+		defer conn.Close()
+
+		resp, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		if err != nil {
+			requestErr = err
+			return
+		}
+		status = resp.GetStatus()
+
+		httpResp, err := http.Get("http://" + addr + "/")
+		if err != nil {
+			requestErr = err
+			return
+		}
+		defer httpResp.Body.Close()
+
+		b, err := io.ReadAll(httpResp.Body)
+		if err != nil {
+			requestErr = err
+			return
+		}
+		body = string(b)
+
+		debugResp, err := http.Get("http://" + addr + "/debug/vars")
+		if err != nil {
+			requestErr = err
+			return
+		}
+		defer debugResp.Body.Close()
+		debugStatus = debugResp.StatusCode
+	}()
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, requestErr)
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, status)
+	require.Equal(t, "hello", body)
+	require.Equal(t, http.StatusOK, debugStatus)
+
+	// the port is closed once the application is shutdown
+	_, err = net.Dial("tcp", addr)
+	require.Error(t, err)
+}
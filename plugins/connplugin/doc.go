@@ -0,0 +1,3 @@
+// Package connplugin provides a tracker of the connections accepted by wrapped listeners, closing idle connections and
+// waiting for active ones to complete when the application is shutdown.
+package connplugin
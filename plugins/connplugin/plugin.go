@@ -0,0 +1,283 @@
+package connplugin
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// DefaultTimeout is how long active connections are waited on during shutdown when no timeout is configured.
+const DefaultTimeout = 30 * time.Second
+
+// Tracker tracks the connections accepted by the listeners it wraps. Connections are considered active until marked
+// idle, either by the server using Idle and Active or, for HTTP servers, by configuring ConnState on the server.
+type Tracker struct {
+	mu       sync.Mutex
+	conns    map[*conn]struct{}
+	draining bool
+	closed   chan struct{}
+}
+
+// New returns a tracker without any connections.
+func New() *Tracker {
+	return &Tracker{conns: make(map[*conn]struct{})}
+}
+
+// Listener wraps the provided listener, tracking each connection it accepts until the connection is closed. The
+// signature matches httpplugin.WithListener, allowing the tracker to be used by the HTTP plugin.
+func (t *Tracker) Listener(l net.Listener) net.Listener {
+	return &listener{Listener: l, tracker: t}
+}
+
+// Idle marks the provided connection as idle, closing it should the tracker be draining.
+func (t *Tracker) Idle(c net.Conn) {
+	tracked, ok := unwrap(c)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	tracked.idle = true
+	draining := t.draining
+	t.mu.Unlock()
+
+	if draining {
+		_ = tracked.Close()
+	}
+}
+
+// Active marks the provided connection as active.
+func (t *Tracker) Active(c net.Conn) {
+	tracked, ok := unwrap(c)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tracked.idle = false
+}
+
+// ConnState marks connections as idle or active as their state changes, and is intended to be configured as the
+// ConnState of an http.Server. Hijacked connections are no longer tracked.
+func (t *Tracker) ConnState(c net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateActive:
+		t.Active(c)
+	case http.StateIdle:
+		t.Idle(c)
+	case http.StateHijacked, http.StateClosed:
+		if tracked, ok := unwrap(c); ok {
+			t.remove(tracked)
+		}
+	}
+}
+
+// Len returns the number of connections tracked.
+func (t *Tracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.conns)
+}
+
+// Shutdown closes the idle connections and waits for the active connections to be closed, closing each connection as it
+// becomes idle. Should the provided context be done first, the remaining connections are closed forcefully.
+func (t *Tracker) Shutdown(ctx context.Context) error {
+	t.mu.Lock()
+	t.draining = true
+	closed := t.closed
+	idle := make([]*conn, 0, len(t.conns))
+	for c := range t.conns {
+		if c.idle {
+			idle = append(idle, c)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, c := range idle {
+		_ = c.Close()
+	}
+
+	if closed == nil {
+		return nil
+	}
+
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		active := t.Len()
+		t.closeAll()
+		return fmt.Errorf("%d connections active: %w", active, ctx.Err())
+	}
+}
+
+func (t *Tracker) add(c *conn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.conns) == 0 {
+		t.closed = make(chan struct{})
+	}
+	t.conns[c] = struct{}{}
+}
+
+func (t *Tracker) remove(c *conn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.conns[c]; !ok {
+		return
+	}
+
+	delete(t.conns, c)
+	if len(t.conns) == 0 {
+		close(t.closed)
+		t.closed = nil
+	}
+}
+
+func (t *Tracker) closeAll() {
+	t.mu.Lock()
+	conns := make([]*conn, 0, len(t.conns))
+	for c := range t.conns {
+		conns = append(conns, c)
+	}
+	t.mu.Unlock()
+
+	for _, c := range conns {
+		_ = c.Close()
+	}
+}
+
+// reset allows the tracker to be reused once the application is restarted.
+func (t *Tracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.draining = false
+}
+
+type listener struct {
+	net.Listener
+	tracker *Tracker
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := &conn{Conn: c, tracker: l.tracker}
+	l.tracker.add(tracked)
+	return tracked, nil
+}
+
+type conn struct {
+	net.Conn
+	tracker *Tracker
+	idle    bool
+
+	once sync.Once
+	err  error
+}
+
+func (c *conn) Close() error {
+	c.once.Do(func() {
+		c.err = c.Conn.Close()
+		c.tracker.remove(c)
+	})
+	return c.err
+}
+
+// unwrap returns the tracked connection underlying the provided connection, which servers may have wrapped using TLS.
+func unwrap(c net.Conn) (*conn, bool) {
+	if tlsConn, ok := c.(*tls.Conn); ok {
+		c = tlsConn.NetConn()
+	}
+
+	tracked, ok := c.(*conn)
+	return tracked, ok
+}
+
+// Option configures the plugin.
+type Option func(p *plugin)
+
+// WithTimeout configures how long active connections are waited on during shutdown.
+func WithTimeout(timeout time.Duration) Option {
+	return func(p *plugin) {
+		p.timeout = timeout
+	}
+}
+
+// Plugin returns a plugin shutting down the tracker (see Tracker.Shutdown) when the application is drained (see
+// lifecycle.Drainer), closing the connections still active once the timeout (DefaultTimeout unless configured using
+// WithTimeout) is exceeded. Plugins are drained in the reverse order they were registered, so register the plugin after
+// the servers using the tracker.
+func (t *Tracker) Plugin(opts ...Option) lifecycle.Plugin {
+	p := &plugin{
+		tracker: t,
+		timeout: DefaultTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	tracker *Tracker
+	timeout time.Duration
+}
+
+func (p *plugin) Name() string {
+	return "connections"
+}
+
+func (p *plugin) InitializeContext(_ context.Context, _ *lifecycle.Application) error {
+	p.tracker.reset()
+	return nil
+}
+
+func (p *plugin) RunContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) StartContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) Drain(ctx context.Context, _ *lifecycle.Application) error {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+	return p.tracker.Shutdown(ctx)
+}
+
+func (p *plugin) ShutdownContext(ctx context.Context, app *lifecycle.Application) error {
+	return p.Drain(ctx, app)
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	return p.InitializeContext(app.Context(), app)
+}
+
+func (p *plugin) Shutdown(app *lifecycle.Application) error {
+	return p.ShutdownContext(app.Context(), app)
+}
+
+var _ lifecycle.PluginContext = &plugin{}
+var _ lifecycle.Drainer = &plugin{}
@@ -0,0 +1,129 @@
+package connplugin
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+	"github.com/effxhq/go-lifecycle/plugins/httpplugin"
+)
+
+func Test_Plugin(t *testing.T) {
+	// reserve a free port for the server to listen on
+	reserved, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := reserved.Addr().String()
+	require.NoError(t, reserved.Close())
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/idle", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/active", func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+	})
+
+	tracker := New()
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(
+		httpplugin.New(addr, mux,
+			httpplugin.WithListener(tracker.Listener),
+			httpplugin.WithServer(func(server *http.Server) {
+				server.ConnState = tracker.ConnState
+			}),
+		),
+		tracker.Plugin(),
+	)
+
+	var idleErr, activeErr error
+	var tracked int
+	status := 0
+
+	go func() {
+		<-app.Ready()
+		defer app.Shutdown(nil)
+
+		// a keep-alive connection is left idle after its request completes
+		idle := &http.Client{Transport: &http.Transport{}}
+		resp, err := idle.Get("http://" + addr + "/idle")
+		if err != nil {
+			idleErr = err
+			return
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		served := make(chan struct{})
+		go func() {
+			defer close(served)
+
+			active := &http.Client{Transport: &http.Transport{}}
+			resp, err := active.Get("http://" + addr + "/active")
+			if err != nil {
+				activeErr = err
+				return
+			}
+			defer resp.Body.Close()
+			status = resp.StatusCode
+		}()
+		<-entered
+
+		shutdown := make(chan error, 1)
+		go func() {
+			shutdown <- tracker.Shutdown(context.Background())
+		}()
+
+		// only the active connection remains once the idle connection is closed
+		for tracker.Len() > 1 {
+			time.Sleep(time.Millisecond)
+		}
+		tracked = tracker.Len()
+
+		close(release)
+		<-served
+		idleErr = <-shutdown
+	}()
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, idleErr)
+	require.NoError(t, activeErr)
+	require.Equal(t, 1, tracked)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, 0, tracker.Len())
+}
+
+func Test_Tracker_Timeout(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	tracker := New()
+	listener := tracker.Listener(l)
+	defer listener.Close()
+
+	client, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	require.Equal(t, 1, tracker.Len())
+
+	// connections are active until marked idle
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = tracker.Shutdown(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.EqualError(t, err, "1 connections active: context deadline exceeded")
+	require.Equal(t, 0, tracker.Len())
+
+	_, err = conn.Write([]byte("ping"))
+	require.ErrorIs(t, err, net.ErrClosed)
+}
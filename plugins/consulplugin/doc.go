@@ -0,0 +1,2 @@
+// Package consulplugin provides a plugin registering the application as a service in Consul while it is running.
+package consulplugin
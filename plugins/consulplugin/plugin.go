@@ -0,0 +1,257 @@
+package consulplugin
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+const (
+	// DefaultTTL is the TTL of the check registered when the registration does not include any checks.
+	DefaultTTL = 10 * time.Second
+	// DefaultDeregisterCriticalServiceAfter is how long Consul waits before deregistering the service once the check
+	// registered by default is critical, cleaning up after processes that exit without deregistering.
+	DefaultDeregisterCriticalServiceAfter = time.Minute
+)
+
+// Option configures the plugin.
+type Option func(p *plugin)
+
+// WithConfig configures the Consul client. By default, the client is configured using api.DefaultConfig, which reads
+// the CONSUL_HTTP_ADDR and related environment variables.
+func WithConfig(config *api.Config) Option {
+	return func(p *plugin) {
+		p.config = config
+	}
+}
+
+// WithDeregisterDelay configures how long the plugin waits after deregistering the service before the plugins
+// registered ahead of it are drained, giving clients time to observe the deregistration and stop sending traffic.
+func WithDeregisterDelay(delay time.Duration) Option {
+	return func(p *plugin) {
+		p.delay = delay
+	}
+}
+
+// Plugin returns a plugin registering the provided service with the local Consul agent once the application has
+// started and is ready (see Application.Ready). When the registration does not include any checks, a TTL check is
+// registered and passed for as long as the plugins of the application are healthy (see Application.Health). The
+// service is deregistered as soon as the application begins shutting down, and the plugin waits for the
+// deregistration delay (see WithDeregisterDelay) when drained (see lifecycle.Drainer). Plugins are drained in the
+// reverse order they were registered, so register the plugin last to keep the servers accepting traffic during the
+// delay. When the plugin is shutdown without being drained (such as when the application restarts), the delay elapses
+// before the plugin is shutdown. The provided registration is copied rather than modified.
+func Plugin(registration *api.AgentServiceRegistration, opts ...Option) lifecycle.Plugin {
+	copied := *registration
+
+	p := &plugin{registration: &copied}
+	if p.registration.ID == "" {
+		p.registration.ID = p.registration.Name
+	}
+
+	if p.registration.Check == nil && len(p.registration.Checks) == 0 {
+		p.ttl = DefaultTTL
+		p.registration.Check = &api.AgentServiceCheck{
+			CheckID:                        p.checkID(),
+			TTL:                            DefaultTTL.String(),
+			DeregisterCriticalServiceAfter: DefaultDeregisterCriticalServiceAfter.String(),
+		}
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	registration *api.AgentServiceRegistration
+	config       *api.Config
+	delay        time.Duration
+	ttl          time.Duration
+
+	client *api.Client
+
+	mu           sync.Mutex
+	registered   bool
+	deregistered chan struct{}
+	delayed      bool
+}
+
+func (p *plugin) Name() string {
+	return "consul"
+}
+
+func (p *plugin) checkID() string {
+	return "service:" + p.registration.ID
+}
+
+func (p *plugin) InitializeContext(_ context.Context, app *lifecycle.Application) error {
+	config := p.config
+	if config == nil {
+		config = api.DefaultConfig()
+	}
+
+	client, err := api.NewClient(config)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.client = client
+	p.deregistered = make(chan struct{})
+	p.delayed = false
+	p.mu.Unlock()
+
+	app.OnStateChange(func(_, to lifecycle.State) {
+		if to == lifecycle.StateShutdown {
+			p.deregister(app)
+		}
+	})
+	return nil
+}
+
+func (p *plugin) RunContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) StartContext(_ context.Context, app *lifecycle.Application) error {
+	app.Go(func(ctx context.Context) error {
+		select {
+		case <-app.Ready():
+		case <-ctx.Done():
+			return nil
+		}
+
+		if err := p.register(app); err != nil {
+			return err
+		}
+
+		if p.ttl > 0 {
+			p.passTTL(ctx, app)
+		}
+		return nil
+	})
+	return nil
+}
+
+func (p *plugin) Drain(ctx context.Context, app *lifecycle.Application) error {
+	return p.await(ctx, app)
+}
+
+func (p *plugin) ShutdownContext(ctx context.Context, app *lifecycle.Application) error {
+	return p.await(ctx, app)
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	return p.InitializeContext(app.Context(), app)
+}
+
+func (p *plugin) Start(app *lifecycle.Application) error {
+	return p.StartContext(app.Context(), app)
+}
+
+func (p *plugin) Shutdown(app *lifecycle.Application) error {
+	return p.ShutdownContext(app.Context(), app)
+}
+
+var _ lifecycle.PluginContext = &plugin{}
+var _ lifecycle.Drainer = &plugin{}
+
+// await deregisters the service and waits for the deregistration delay, unless it has already been waited for.
+func (p *plugin) await(ctx context.Context, app *lifecycle.Application) error {
+	p.deregister(app)
+
+	p.mu.Lock()
+	deregistered, delayed := p.deregistered, p.delayed
+	p.delayed = true
+	p.mu.Unlock()
+
+	select {
+	case <-deregistered:
+	default:
+		// the service was never registered
+		return nil
+	}
+
+	if delayed {
+		return nil
+	}
+
+	timer := time.NewTimer(p.delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// register registers the service, unless the application has begun shutting down.
+func (p *plugin) register(app *lifecycle.Application) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if app.State() >= lifecycle.StateShutdown {
+		return nil
+	}
+
+	if err := p.client.Agent().ServiceRegister(p.registration); err != nil {
+		return err
+	}
+
+	p.registered = true
+	return nil
+}
+
+// deregister deregisters the service once, logging failures since they should not interrupt shutdown. Consul
+// eventually removes services whose checks are critical.
+func (p *plugin) deregister(app *lifecycle.Application) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.registered {
+		return
+	}
+	p.registered = false
+
+	if err := p.client.Agent().ServiceDeregister(p.registration.ID); err != nil {
+		app.Logger().Warn("failed to deregister consul service", "service", p.registration.ID, "error", err)
+	}
+	close(p.deregistered)
+}
+
+// passTTL updates the TTL check on an interval until the context is canceled, passing the check while the plugins of
+// the application are healthy.
+func (p *plugin) passTTL(ctx context.Context, app *lifecycle.Application) {
+	ticker := time.NewTicker(p.ttl / 2)
+	defer ticker.Stop()
+
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, p.ttl/2)
+		err := app.Health(checkCtx).Err()
+		cancel()
+
+		status, output := api.HealthPassing, "ok"
+		if err != nil {
+			status, output = api.HealthCritical, err.Error()
+		}
+
+		if err := p.client.Agent().UpdateTTL(p.checkID(), output, status); err != nil {
+			app.Logger().Warn("failed to update consul check", "check", p.checkID(), "error", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package consulplugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+var errStart = errors.New("failed to start")
+
+type agent struct {
+	mu           sync.Mutex
+	requests     []string
+	registration api.AgentServiceRegistration
+	deregistered time.Time
+}
+
+func (a *agent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/v1/agent/service/register":
+		if err := json.NewDecoder(r.Body).Decode(&a.registration); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	case "/v1/agent/service/deregister/api":
+		a.deregistered = time.Now()
+	}
+
+	a.requests = append(a.requests, r.Method+" "+r.URL.Path)
+}
+
+func (a *agent) seen(request string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, r := range a.requests {
+		if r == request {
+			return true
+		}
+	}
+	return false
+}
+
+// backend stands in for a plugin serving traffic until it is drained.
+type backend struct {
+	lifecycle.PluginFuncs
+	drained time.Time
+}
+
+func (b *backend) Drain(_ context.Context, _ *lifecycle.Application) error {
+	b.drained = time.Now()
+	return nil
+}
+
+func Test_Plugin(t *testing.T) {
+	consul := &agent{}
+	server := httptest.NewServer(consul)
+	defer server.Close()
+
+	service := &backend{}
+	registration := &api.AgentServiceRegistration{Name: "api", Port: 8080}
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(
+		service,
+		Plugin(
+			registration,
+			WithConfig(&api.Config{Address: server.URL}),
+			WithDeregisterDelay(50*time.Millisecond),
+		),
+	)
+
+	go func() {
+		<-app.Ready()
+		defer app.Shutdown(nil)
+
+		for !consul.seen("PUT /v1/agent/check/update/service:api") {
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	require.NoError(t, app.StartE())
+
+	consul.mu.Lock()
+	defer consul.mu.Unlock()
+
+	require.Equal(t, []string{
+		"PUT /v1/agent/service/register",
+		"PUT /v1/agent/check/update/service:api",
+		"PUT /v1/agent/service/deregister/api",
+	}, consul.requests)
+
+	require.Equal(t, "api", consul.registration.ID)
+	require.Equal(t, "service:api", consul.registration.Check.CheckID)
+	require.Equal(t, DefaultTTL.String(), consul.registration.Check.TTL)
+	require.GreaterOrEqual(t, service.drained.Sub(consul.deregistered), 50*time.Millisecond)
+
+	// the registration provided is left untouched
+	require.Empty(t, registration.ID)
+	require.Nil(t, registration.Check)
+}
+
+func Test_Plugin_NotReady(t *testing.T) {
+	consul := &agent{}
+	server := httptest.NewServer(consul)
+	defer server.Close()
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(
+		Plugin(&api.AgentServiceRegistration{Name: "api"}, WithConfig(&api.Config{Address: server.URL})),
+		&lifecycle.PluginFuncs{
+			StartFunc: func(app *lifecycle.Application) error {
+				return errStart
+			},
+		},
+	)
+
+	require.ErrorIs(t, app.StartE(), errStart)
+	require.Empty(t, consul.requests, "service registered before ready")
+}
@@ -0,0 +1,3 @@
+// Package drainplugin provides HTTP middleware and gRPC interceptors rejecting requests once the application begins
+// shutting down, and a plugin waiting for in-flight requests to complete before the remaining plugins are shutdown.
+package drainplugin
@@ -0,0 +1,162 @@
+package drainplugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// DefaultTimeout is how long in-flight requests are waited on during shutdown when no timeout is configured.
+const DefaultTimeout = 30 * time.Second
+
+// Drain tracks the requests served through its middleware and interceptors as work in-flight (see
+// lifecycle.Application.Track), rejecting new requests once the application begins shutting down.
+type Drain struct {
+	app *lifecycle.Application
+}
+
+// New returns a Drain tied to the state of the provided application.
+func New(app *lifecycle.Application) *Drain {
+	return &Drain{app: app}
+}
+
+// InFlight returns the number of units of work currently in-flight, including the requests served through the drain
+// (see lifecycle.Application.InFlight).
+func (d *Drain) InFlight() int {
+	return d.app.InFlight()
+}
+
+// Wait blocks until no work is in-flight or the provided context is done.
+func (d *Drain) Wait(ctx context.Context) error {
+	select {
+	case <-d.app.Idle():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Middleware wraps the provided handler, responding with 503 Service Unavailable once the application begins shutting
+// down. Connections are closed along with the response, so clients reconnect to another instance.
+func (d *Drain) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		done, ok := d.app.Track()
+		if !ok {
+			w.Header().Set("Connection", "close")
+			http.Error(w, "application is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		defer done()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UnaryServerInterceptor returns an interceptor failing unary RPCs with codes.Unavailable once the application begins
+// shutting down.
+func (d *Drain) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		done, ok := d.app.Track()
+		if !ok {
+			return nil, status.Error(codes.Unavailable, "application is shutting down")
+		}
+		defer done()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor failing streaming RPCs with codes.Unavailable once the application
+// begins shutting down.
+func (d *Drain) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		done, ok := d.app.Track()
+		if !ok {
+			return status.Error(codes.Unavailable, "application is shutting down")
+		}
+		defer done()
+
+		return handler(srv, ss)
+	}
+}
+
+// Option configures the plugin.
+type Option func(p *plugin)
+
+// WithTimeout configures how long in-flight requests are waited on during shutdown.
+func WithTimeout(timeout time.Duration) Option {
+	return func(p *plugin) {
+		p.timeout = timeout
+	}
+}
+
+// Plugin returns a plugin waiting for in-flight requests to complete when the application is drained (see
+// lifecycle.Drainer), failing should the timeout (DefaultTimeout unless configured using WithTimeout) be exceeded.
+// Since every plugin is drained before any is shutdown, the plugins the requests depend upon remain available. Plugins
+// are drained in the reverse order they were registered, so register the plugin after the servers.
+func (d *Drain) Plugin(opts ...Option) lifecycle.Plugin {
+	p := &plugin{
+		drain:   d,
+		timeout: DefaultTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	drain   *Drain
+	timeout time.Duration
+}
+
+func (p *plugin) Name() string {
+	return "drain"
+}
+
+func (p *plugin) InitializeContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) RunContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) StartContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) Drain(ctx context.Context, _ *lifecycle.Application) error {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	if err := p.drain.Wait(ctx); err != nil {
+		return fmt.Errorf("%d units of work in-flight: %w", p.drain.InFlight(), err)
+	}
+	return nil
+}
+
+func (p *plugin) ShutdownContext(ctx context.Context, app *lifecycle.Application) error {
+	return p.Drain(ctx, app)
+}
+
+func (p *plugin) Shutdown(app *lifecycle.Application) error {
+	return p.ShutdownContext(app.Context(), app)
+}
+
+var _ lifecycle.PluginContext = &plugin{}
+var _ lifecycle.Drainer = &plugin{}
@@ -0,0 +1,96 @@
+package drainplugin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+func Test_Drain(t *testing.T) {
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	drain := New(app)
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	handler := drain.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+	}))
+
+	app.Initialize(drain.Plugin())
+
+	inFlight := httptest.NewRecorder()
+	rejected := httptest.NewRecorder()
+	var rejectedErr error
+	served := make(chan struct{})
+
+	go func() {
+		<-app.Ready()
+
+		go func() {
+			defer close(served)
+			handler.ServeHTTP(inFlight, httptest.NewRequest(http.MethodGet, "/", nil))
+		}()
+		<-entered
+
+		go app.Shutdown(nil)
+		for app.State() < lifecycle.StateShutdown {
+			time.Sleep(time.Millisecond)
+		}
+
+		handler.ServeHTTP(rejected, httptest.NewRequest(http.MethodGet, "/", nil))
+		_, rejectedErr = drain.UnaryServerInterceptor()(
+			context.Background(), nil, nil,
+			func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil },
+		)
+		close(release)
+	}()
+
+	require.NoError(t, app.StartE())
+	<-served
+
+	require.Equal(t, http.StatusOK, inFlight.Code)
+	require.Equal(t, http.StatusServiceUnavailable, rejected.Code)
+	require.Equal(t, "close", rejected.Header().Get("Connection"))
+	require.Equal(t, codes.Unavailable, status.Code(rejectedErr))
+	require.Equal(t, 0, drain.InFlight())
+}
+
+func Test_Drain_Timeout(t *testing.T) {
+	// requests are work in-flight, which the application also waits on
+	app := lifecycle.NewApplication(
+		lifecycle.WithTerminator(func(err error) {}),
+		lifecycle.WithWorkTimeout(10*time.Millisecond),
+	)
+	drain := New(app)
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	handler := drain.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+	}))
+
+	app.Initialize(drain.Plugin(WithTimeout(10 * time.Millisecond)))
+
+	go func() {
+		<-app.Ready()
+		go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		<-entered
+		app.Shutdown(nil)
+	}()
+
+	err := app.StartE()
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "1 units of work in-flight")
+}
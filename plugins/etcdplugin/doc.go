@@ -0,0 +1,3 @@
+// Package etcdplugin provides a plugin registering the application in etcd under a key bound to a lease, which is kept
+// alive while the application is running.
+package etcdplugin
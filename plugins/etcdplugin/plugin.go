@@ -0,0 +1,224 @@
+package etcdplugin
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+const (
+	// DefaultTTL is the TTL of the lease when no TTL is configured. Should the process exit without revoking the lease,
+	// the key is removed once the TTL expires.
+	DefaultTTL = 10 * time.Second
+	// DefaultRevokeTimeout bounds how long revoking the lease may take once the application begins shutting down.
+	DefaultRevokeTimeout = 5 * time.Second
+)
+
+// Option configures the plugin.
+type Option func(p *plugin)
+
+// WithConfig configures the client constructed by the plugin. By default, the client connects to localhost:2379.
+func WithConfig(config clientv3.Config) Option {
+	return func(p *plugin) {
+		p.config = config
+	}
+}
+
+// WithClient configures the plugin to use an existing client, which is not closed by the plugin.
+func WithClient(client *clientv3.Client) Option {
+	return func(p *plugin) {
+		p.client = client
+	}
+}
+
+// WithTTL configures the TTL of the lease.
+func WithTTL(ttl time.Duration) Option {
+	return func(p *plugin) {
+		p.ttl = ttl
+	}
+}
+
+// Plugin returns a plugin writing the provided key and value under a lease once the application has started and is
+// ready (see Application.Ready). The lease is kept alive from a go-routine managed by the application, and the key is
+// written again under a new lease should the lease be lost (for example, while etcd is unreachable). The lease is
+// revoked as soon as the application begins shutting down, removing the key so discovery stops routing to the instance
+// promptly.
+func Plugin(key, value string, opts ...Option) lifecycle.Plugin {
+	p := &plugin{
+		key:    key,
+		value:  value,
+		config: clientv3.Config{Endpoints: []string{"localhost:2379"}},
+		ttl:    DefaultTTL,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	key    string
+	value  string
+	config clientv3.Config
+	ttl    time.Duration
+
+	client *clientv3.Client
+	owned  bool
+
+	mu    sync.Mutex
+	lease clientv3.LeaseID
+}
+
+func (p *plugin) Name() string {
+	return "etcd"
+}
+
+func (p *plugin) InitializeContext(ctx context.Context, app *lifecycle.Application) error {
+	if p.client == nil || p.owned {
+		// the client outlives initialization, so it is not closed once the initialize timeout is exceeded
+		config := p.config
+		config.Context = context.WithoutCancel(ctx)
+
+		client, err := clientv3.New(config)
+		if err != nil {
+			return err
+		}
+
+		p.client = client
+		p.owned = true
+	}
+
+	app.OnStateChange(func(_, to lifecycle.State) {
+		if to == lifecycle.StateShutdown {
+			p.revoke(app)
+		}
+	})
+	return nil
+}
+
+func (p *plugin) RunContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) StartContext(_ context.Context, app *lifecycle.Application) error {
+	app.Go(func(ctx context.Context) error {
+		select {
+		case <-app.Ready():
+		case <-ctx.Done():
+			return nil
+		}
+
+		p.keepAlive(ctx, app)
+		return nil
+	})
+	return nil
+}
+
+func (p *plugin) ShutdownContext(_ context.Context, app *lifecycle.Application) error {
+	p.revoke(app)
+
+	if p.owned {
+		return p.client.Close()
+	}
+	return nil
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	return p.InitializeContext(app.Context(), app)
+}
+
+func (p *plugin) Start(app *lifecycle.Application) error {
+	return p.StartContext(app.Context(), app)
+}
+
+func (p *plugin) Shutdown(app *lifecycle.Application) error {
+	return p.ShutdownContext(app.Context(), app)
+}
+
+var _ lifecycle.PluginContext = &plugin{}
+
+// keepAlive registers the key and keeps its lease alive until the context is canceled, registering the key again
+// should the lease be lost. Failed registrations are retried every half TTL.
+func (p *plugin) keepAlive(ctx context.Context, app *lifecycle.Application) {
+	for {
+		lease, err := p.register(ctx, app)
+		if err == nil && lease == 0 {
+			// the application has begun shutting down
+			return
+		}
+
+		if err == nil {
+			var responses <-chan *clientv3.LeaseKeepAliveResponse
+			if responses, err = p.client.KeepAlive(ctx, lease); err == nil {
+				// responses are delivered until the context is canceled or the lease is lost (or revoked)
+				for range responses {
+				}
+			}
+		}
+
+		if ctx.Err() != nil || app.State() >= lifecycle.StateShutdown {
+			return
+		}
+
+		if err == nil {
+			// the lease expired, likely while etcd was unreachable
+			app.Logger().Warn("lost etcd lease, registering again", "key", p.key)
+			continue
+		}
+
+		app.Logger().Warn("failed to register with etcd", "key", p.key, "error", err)
+
+		select {
+		case <-time.After(p.ttl / 2):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// register writes the key under a new lease, unless the application has begun shutting down.
+func (p *plugin) register(ctx context.Context, app *lifecycle.Application) (clientv3.LeaseID, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if app.State() >= lifecycle.StateShutdown {
+		return 0, nil
+	}
+
+	grant, err := p.client.Grant(ctx, int64(p.ttl/time.Second))
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := p.client.Put(ctx, p.key, p.value, clientv3.WithLease(grant.ID)); err != nil {
+		return 0, err
+	}
+
+	p.lease = grant.ID
+	return grant.ID, nil
+}
+
+// revoke revokes the lease once, logging failures since they should not interrupt shutdown. The key is removed once
+// the lease expires regardless.
+func (p *plugin) revoke(app *lifecycle.Application) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.lease == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRevokeTimeout)
+	defer cancel()
+
+	if _, err := p.client.Revoke(ctx, p.lease); err != nil {
+		app.Logger().Warn("failed to revoke etcd lease", "key", p.key, "error", err)
+	}
+	p.lease = 0
+}
@@ -0,0 +1,157 @@
+package etcdplugin
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// fakeEtcd implements the parts of the KV and Lease APIs used by the plugin.
+type fakeEtcd struct {
+	clientv3.KV
+	clientv3.Lease
+
+	mu         sync.Mutex
+	keys       map[string]string
+	leases     map[clientv3.LeaseID][]string
+	next       clientv3.LeaseID
+	keepAlives chan struct{}
+	alive      map[clientv3.LeaseID]chan *clientv3.LeaseKeepAliveResponse
+}
+
+func newFakeEtcd() *fakeEtcd {
+	return &fakeEtcd{
+		keys:       make(map[string]string),
+		leases:     make(map[clientv3.LeaseID][]string),
+		keepAlives: make(chan struct{}, 1),
+		alive:      make(map[clientv3.LeaseID]chan *clientv3.LeaseKeepAliveResponse),
+	}
+}
+
+func (f *fakeEtcd) Grant(_ context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.next++
+	f.leases[f.next] = nil
+	return &clientv3.LeaseGrantResponse{ID: f.next, TTL: ttl}, nil
+}
+
+// Put binds the key to the lease granted last, since the lease of an operation is not exported.
+func (f *fakeEtcd) Put(_ context.Context, key, val string, _ ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.keys[key] = val
+	f.leases[f.next] = append(f.leases[f.next], key)
+	return &clientv3.PutResponse{}, nil
+}
+
+func (f *fakeEtcd) KeepAlive(
+	ctx context.Context, id clientv3.LeaseID,
+) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	responses := make(chan *clientv3.LeaseKeepAliveResponse)
+	f.alive[id] = responses
+	f.keepAlives <- struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		f.expire(id)
+	}()
+	return responses, nil
+}
+
+func (f *fakeEtcd) Revoke(_ context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error) {
+	f.mu.Lock()
+	for _, key := range f.leases[id] {
+		delete(f.keys, key)
+	}
+	delete(f.leases, id)
+	f.mu.Unlock()
+
+	f.expire(id)
+	return &clientv3.LeaseRevokeResponse{}, nil
+}
+
+// expire stops keeping the lease alive, closing the channel returned by KeepAlive.
+func (f *fakeEtcd) expire(id clientv3.LeaseID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if responses, ok := f.alive[id]; ok {
+		close(responses)
+		delete(f.alive, id)
+	}
+}
+
+func (f *fakeEtcd) snapshot() map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := make(map[string]string, len(f.keys))
+	for k, v := range f.keys {
+		keys[k] = v
+	}
+	return keys
+}
+
+func newFakeClient(f *fakeEtcd) *clientv3.Client {
+	client := clientv3.NewCtxClient(context.Background())
+	client.KV = f
+	client.Lease = f
+	return client
+}
+
+func Test_Plugin(t *testing.T) {
+	etcd := newFakeEtcd()
+
+	var registered, revoked map[string]string
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(Plugin("/services/api/1", "10.0.0.1:8080", WithClient(newFakeClient(etcd))))
+
+	app.OnStateChange(func(_, to lifecycle.State) {
+		// listeners are invoked in order, so the lease has been revoked by the plugin's listener
+		if to == lifecycle.StateShutdown {
+			revoked = etcd.snapshot()
+		}
+	})
+
+	go func() {
+		<-app.Ready()
+		<-etcd.keepAlives
+		registered = etcd.snapshot()
+
+		// the key is written again should the lease be lost, leaving the lost lease to expire
+		etcd.expire(1)
+		<-etcd.keepAlives
+
+		app.Shutdown(nil)
+	}()
+
+	require.NoError(t, app.StartE())
+	require.Equal(t, map[string]string{"/services/api/1": "10.0.0.1:8080"}, registered)
+	require.Empty(t, revoked)
+	require.Equal(t, map[clientv3.LeaseID][]string{1: {"/services/api/1"}}, etcd.leases, "lease not revoked")
+}
+
+func Test_Plugin_InitializeContext(t *testing.T) {
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	p := Plugin("/services/api/1", "10.0.0.1:8080").(*plugin)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, p.InitializeContext(ctx, app))
+	defer p.client.Close()
+
+	// the context of initialization is canceled once initialized (such as when using lifecycle.WithInitializeTimeout)
+	cancel()
+	require.NoError(t, p.client.Ctx().Err(), "client closed along with the context of initialization")
+}
@@ -0,0 +1,3 @@
+// Package expvarplugin provides a plugin that publishes the lifecycle of an application using the expvar package, so
+// existing expvar-based tooling can scrape its state, uptime, plugins, and last error.
+package expvarplugin
@@ -0,0 +1,89 @@
+package expvarplugin
+
+import (
+	"expvar"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// DefaultName is the name the lifecycle is published under when no name is provided.
+const DefaultName = "lifecycle"
+
+// Plugin returns a plugin that publishes the lifecycle of the application as an expvar under the provided name
+// (DefaultName when empty). Since expvars cannot be unpublished, the expvar is published once (remaining published
+// across restarts of the application), and initializing the plugin fails should the name already be published by
+// someone else.
+func Plugin(name string) lifecycle.Plugin {
+	if name == "" {
+		name = DefaultName
+	}
+
+	return &plugin{name: name}
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	name string
+
+	mu        sync.Mutex
+	app       *lifecycle.Application
+	published bool
+	started   time.Time
+}
+
+func (p *plugin) Name() string {
+	return "expvar"
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.published {
+		if expvar.Get(p.name) != nil {
+			return fmt.Errorf("expvar %q has already been published", p.name)
+		}
+
+		p.started = time.Now()
+		expvar.Publish(p.name, expvar.Func(func() interface{} {
+			return p.snapshot()
+		}))
+		p.published = true
+	}
+
+	p.app = app
+	return nil
+}
+
+// Snapshot is the value published for the application.
+type Snapshot struct {
+	State     string   `json:"state"`
+	Uptime    float64  `json:"uptime_seconds"`
+	Plugins   []string `json:"plugins"`
+	LastError string   `json:"last_error,omitempty"`
+	Reason    string   `json:"shutdown_reason,omitempty"`
+}
+
+func (p *plugin) snapshot() Snapshot {
+	p.mu.Lock()
+	app, started := p.app, p.started
+	p.mu.Unlock()
+
+	snapshot := Snapshot{
+		State:   app.State().String(),
+		Uptime:  time.Since(started).Seconds(),
+		Plugins: app.Plugins(),
+	}
+
+	if err := app.Err(); err != nil {
+		snapshot.LastError = err.Error()
+	}
+
+	if reason := app.ShutdownReason(); reason.Kind != lifecycle.ShutdownNone {
+		snapshot.Reason = reason.String()
+	}
+	return snapshot
+}
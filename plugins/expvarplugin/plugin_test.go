@@ -0,0 +1,70 @@
+package expvarplugin
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// published counts the expvars published by the tests, which are named uniquely since expvars cannot be unpublished
+// between repeated runs (such as when using -count).
+var published int32
+
+func uniqueName(name string) string {
+	return fmt.Sprintf("%s_%d", name, atomic.AddInt32(&published, 1))
+}
+
+func Test_Plugin(t *testing.T) {
+	name := uniqueName("test_lifecycle")
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+
+	app.Initialize(Plugin(name), &lifecycle.PluginFuncs{
+		RunFunc: func(app *lifecycle.Application) error {
+			return fmt.Errorf("something went wrong")
+		},
+	})
+
+	require.Error(t, app.RunE())
+
+	snapshot := Snapshot{}
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get(name).String()), &snapshot))
+
+	require.Equal(t, "terminated", snapshot.State)
+	require.Equal(t, []string{"expvar", "plugin[1]"}, snapshot.Plugins)
+	require.Equal(t, "plugin[1] (*lifecycle.PluginFuncs) failed to run: something went wrong", snapshot.LastError)
+	require.Contains(t, snapshot.Reason, "failed")
+
+	duplicate := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	duplicate.Initialize(Plugin(name))
+	require.EqualError(t, duplicate.RunE(),
+		fmt.Sprintf(`expvar (*expvarplugin.plugin) failed to initialize: expvar %q has already been published`, name))
+}
+
+func Test_Plugin_Restart(t *testing.T) {
+	name := uniqueName("test_lifecycle_restart")
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(Plugin(name))
+
+	restarted := make(chan error, 1)
+	app.AfterStart(func(_ context.Context) error {
+		go func() {
+			restarted <- app.Restart()
+			app.Shutdown(nil)
+		}()
+		return nil
+	})
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, <-restarted)
+
+	snapshot := Snapshot{}
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get(name).String()), &snapshot))
+	require.Equal(t, "terminated", snapshot.State)
+}
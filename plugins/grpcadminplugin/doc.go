@@ -0,0 +1,4 @@
+// Package grpcadminplugin provides a plugin mounting a protobuf-defined admin service (see the adminpb package) on a
+// gRPC server managed by another plugin, allowing fleet tooling to inspect, reload, or shutdown the applications it
+// manages.
+package grpcadminplugin
@@ -0,0 +1,30 @@
+module github.com/effxhq/go-lifecycle/plugins/grpcadminplugin
+
+go 1.21
+
+require (
+	github.com/effxhq/go-lifecycle v0.2.0
+	github.com/effxhq/go-lifecycle/plugins/grpcplugin v0.2.0
+	github.com/stretchr/testify v1.8.4
+	google.golang.org/grpc v1.62.1
+	google.golang.org/protobuf v1.32.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/effxhq/go-lifecycle/plugins/tlsplugin v0.2.0 // indirect
+	github.com/fsnotify/fsnotify v1.7.0 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	golang.org/x/net v0.20.0 // indirect
+	golang.org/x/sys v0.17.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240123012728-ef4313101c80 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	github.com/effxhq/go-lifecycle => ../..
+	github.com/effxhq/go-lifecycle/plugins/grpcplugin => ../grpcplugin
+	github.com/effxhq/go-lifecycle/plugins/tlsplugin => ../tlsplugin
+)
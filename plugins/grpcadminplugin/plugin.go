@@ -0,0 +1,166 @@
+package grpcadminplugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/effxhq/go-lifecycle"
+	"github.com/effxhq/go-lifecycle/plugins/grpcadminplugin/adminpb"
+	"github.com/effxhq/go-lifecycle/plugins/grpcplugin"
+)
+
+// Lookup returns the gRPC server attached to the provided context by the plugin with the provided name, matching both
+// grpcplugin.FromContext and cmuxplugin.GRPCServer.
+type Lookup func(ctx context.Context, name string) (*grpc.Server, bool)
+
+// Option configures the plugin.
+type Option func(p *plugin)
+
+// WithServer configures the plugin managing the gRPC server the service is mounted on. By default, the service is
+// mounted on the server of the grpcplugin named grpcplugin.DefaultName. For example, to mount the service on a port
+// multiplexed by the cmuxplugin:
+//
+//	grpcadminplugin.Plugin(grpcadminplugin.WithServer(cmuxplugin.DefaultName, cmuxplugin.GRPCServer))
+func WithServer(name string, lookup Lookup) Option {
+	return func(p *plugin) {
+		p.server = name
+		p.lookup = lookup
+	}
+}
+
+// Plugin returns a plugin mounting the admin service (see NewServer) on a gRPC server managed by another plugin (see
+// WithServer), which the plugin depends on. The service is registered during initialization, before the server begins
+// accepting connections.
+func Plugin(opts ...Option) lifecycle.Plugin {
+	p := &plugin{
+		server: grpcplugin.DefaultName,
+		lookup: grpcplugin.FromContext,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	server string
+	lookup Lookup
+}
+
+func (p *plugin) Name() string {
+	return "grpc-admin"
+}
+
+func (p *plugin) Dependencies() []string {
+	return []string{p.server}
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	server, ok := p.lookup(app.Context(), p.server)
+	if !ok {
+		return fmt.Errorf("grpc server %q not found, is its plugin registered?", p.server)
+	}
+
+	adminpb.RegisterAdminServer(server, NewServer(app))
+	return nil
+}
+
+var _ lifecycle.Dependent = &plugin{}
+
+// NewServer returns an implementation of the admin service managing the provided application, allowing the service to
+// be registered on servers that are not managed by a plugin.
+func NewServer(app *lifecycle.Application) adminpb.AdminServer {
+	return &server{app: app}
+}
+
+type server struct {
+	adminpb.UnimplementedAdminServer
+	app *lifecycle.Application
+}
+
+func (s *server) Status(_ context.Context, _ *adminpb.StatusRequest) (*adminpb.StatusResponse, error) {
+	metadata := s.app.Metadata()
+
+	resp := &adminpb.StatusResponse{
+		Name:     metadata.Name,
+		Version:  metadata.Version,
+		Metadata: metadata.Values,
+		State:    s.app.State().String(),
+		InFlight: int64(s.app.InFlight()),
+	}
+
+	select {
+	case <-s.app.Ready():
+		resp.Ready = true
+	default:
+	}
+
+	if reason := s.app.ShutdownReason(); reason.Kind != lifecycle.ShutdownNone {
+		resp.ShutdownReason = reason.String()
+	}
+
+	for _, plugin := range s.app.PluginStatuses() {
+		timings := make(map[string]*durationpb.Duration, len(plugin.Timings))
+		for phase, duration := range plugin.Timings {
+			timings[string(phase)] = durationpb.New(duration)
+		}
+
+		resp.Plugins = append(resp.Plugins, &adminpb.PluginStatus{
+			Name:    plugin.Name,
+			Type:    plugin.Type,
+			Status:  plugin.Status,
+			Timings: timings,
+		})
+	}
+	return resp, nil
+}
+
+func (s *server) Health(ctx context.Context, _ *adminpb.HealthRequest) (*adminpb.HealthResponse, error) {
+	report := s.app.Health(ctx)
+
+	names := make([]string, 0, len(report.Plugins))
+	for name := range report.Plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resp := &adminpb.HealthResponse{Healthy: report.Healthy()}
+	for _, name := range names {
+		plugin := &adminpb.PluginHealth{Name: name, Healthy: true}
+		if err := report.Plugins[name]; err != nil {
+			plugin.Healthy = false
+			plugin.Error = err.Error()
+		}
+		resp.Plugins = append(resp.Plugins, plugin)
+	}
+	return resp, nil
+}
+
+func (s *server) Shutdown(_ context.Context, _ *adminpb.ShutdownRequest) (*adminpb.ShutdownResponse, error) {
+	if s.app.State() >= lifecycle.StateShutdown {
+		return nil, status.Error(codes.FailedPrecondition, lifecycle.ErrAlreadyTerminated.Error())
+	}
+
+	// the shutdown is not waited on, so the response is sent while the server is drained
+	s.app.Shutdown(nil)
+	return &adminpb.ShutdownResponse{}, nil
+}
+
+func (s *server) Reload(_ context.Context, _ *adminpb.ReloadRequest) (*adminpb.ReloadResponse, error) {
+	if err := s.app.Reload(); err != nil {
+		if errors.Is(err, lifecycle.ErrAlreadyTerminated) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &adminpb.ReloadResponse{}, nil
+}
@@ -0,0 +1,149 @@
+package grpcadminplugin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/effxhq/go-lifecycle"
+	"github.com/effxhq/go-lifecycle/plugins/grpcadminplugin/adminpb"
+	"github.com/effxhq/go-lifecycle/plugins/grpcplugin"
+)
+
+type unhealthyPlugin struct {
+	lifecycle.PluginFuncs
+}
+
+func (p *unhealthyPlugin) Name() string {
+	return "unhealthy"
+}
+
+func (p *unhealthyPlugin) Healthy(_ context.Context) error {
+	return errors.New("connection lost")
+}
+
+type reloader struct {
+	lifecycle.PluginFuncs
+	reloaded int
+}
+
+func (r *reloader) Reload(_ *lifecycle.Application) error {
+	r.reloaded++
+	return nil
+}
+
+func Test_Plugin(t *testing.T) {
+	// reserve a free port for the plugin to listen on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	reloader := &reloader{}
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}), lifecycle.WithName("example"))
+	app.Initialize(
+		grpcplugin.New(addr),
+		Plugin(),
+		&unhealthyPlugin{},
+		reloader,
+	)
+
+	var statusResp *adminpb.StatusResponse
+	var healthResp *adminpb.HealthResponse
+	var callErr error
+	called := make(chan struct{})
+
+	go func() {
+		defer close(called)
+		<-app.Ready()
+
+		conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			callErr = err
+			app.Shutdown(nil)
+			return
+		}
+		defer conn.Close()
+
+		client := adminpb.NewAdminClient(conn)
+		ctx := context.Background()
+
+		if statusResp, callErr = client.Status(ctx, &adminpb.StatusRequest{}); callErr != nil {
+			app.Shutdown(nil)
+			return
+		}
+
+		if healthResp, callErr = client.Health(ctx, &adminpb.HealthRequest{}); callErr != nil {
+			app.Shutdown(nil)
+			return
+		}
+
+		if _, callErr = client.Reload(ctx, &adminpb.ReloadRequest{}); callErr != nil {
+			app.Shutdown(nil)
+			return
+		}
+
+		_, callErr = client.Shutdown(ctx, &adminpb.ShutdownRequest{})
+	}()
+
+	require.NoError(t, app.StartE())
+	<-called
+	require.NoError(t, callErr)
+
+	require.Equal(t, "example", statusResp.GetName())
+	require.Equal(t, lifecycle.StateStarted.String(), statusResp.GetState())
+	require.True(t, statusResp.GetReady())
+	require.Empty(t, statusResp.GetShutdownReason())
+	require.Len(t, statusResp.GetPlugins(), 4)
+	require.Equal(t, grpcplugin.DefaultName, statusResp.GetPlugins()[0].GetName())
+	require.Contains(t, statusResp.GetPlugins()[0].GetTimings(), string(lifecycle.PhaseInitialize))
+
+	require.False(t, healthResp.GetHealthy())
+	require.Len(t, healthResp.GetPlugins(), 1)
+	require.Equal(t, "unhealthy", healthResp.GetPlugins()[0].GetName())
+	require.Equal(t, "connection lost", healthResp.GetPlugins()[0].GetError())
+
+	require.Equal(t, 1, reloader.reloaded)
+	require.Equal(t, lifecycle.ShutdownRequested, app.ShutdownReason().Kind)
+}
+
+func Test_Server_Terminated(t *testing.T) {
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize()
+
+	go func() {
+		<-app.Ready()
+		app.Shutdown(nil)
+	}()
+	require.NoError(t, app.StartE())
+
+	server := NewServer(app)
+
+	_, err := server.Shutdown(context.Background(), &adminpb.ShutdownRequest{})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = server.Reload(context.Background(), &adminpb.ReloadRequest{})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func Test_Plugin_ServerNotFound(t *testing.T) {
+	lookup := func(ctx context.Context, name string) (*grpc.Server, bool) {
+		return nil, false
+	}
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(
+		grpcplugin.New("127.0.0.1:0"),
+		Plugin(WithServer(grpcplugin.DefaultName, lookup)),
+	)
+
+	require.ErrorContains(t, app.StartE(), `grpc server "grpc" not found`)
+}
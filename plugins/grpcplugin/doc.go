@@ -0,0 +1,3 @@
+// Package grpcplugin provides a plugin managing a grpc.Server, gracefully draining in-flight RPCs when the application
+// is shutdown.
+package grpcplugin
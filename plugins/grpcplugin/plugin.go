@@ -0,0 +1,205 @@
+package grpcplugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/effxhq/go-lifecycle"
+	"github.com/effxhq/go-lifecycle/plugins/tlsplugin"
+)
+
+const (
+	// DefaultName is the name of the plugin when no name is configured.
+	DefaultName = "grpc"
+	// DefaultDrainTimeout is how long in-flight RPCs are given to complete during shutdown when no drain timeout is
+	// configured.
+	DefaultDrainTimeout = 30 * time.Second
+)
+
+type contextKey struct {
+	name string
+}
+
+func (k contextKey) String() string {
+	return "grpc server " + k.name
+}
+
+// Option configures the plugin.
+type Option func(p *plugin)
+
+// WithName configures the name of the plugin, allowing several servers to be registered with the same application.
+// The server is attached to the application context under this name (see FromContext).
+func WithName(name string) Option {
+	return func(p *plugin) {
+		p.name = name
+	}
+}
+
+// WithDrainTimeout configures how long in-flight RPCs are given to complete during shutdown. Once exceeded, the server
+// is stopped forcefully, canceling the remaining RPCs.
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(p *plugin) {
+		p.drainTimeout = timeout
+	}
+}
+
+// WithServerOptions configures the options the server is constructed with (such as interceptors or credentials).
+func WithServerOptions(opts ...grpc.ServerOption) Option {
+	return func(p *plugin) {
+		p.serverOpts = append(p.serverOpts, opts...)
+	}
+}
+
+// WithTLS serves gRPC over TLS using the tls.Config provided by the tlsplugin package, whose plugin must also be
+// registered. The certificate served is reloaded as it is rotated.
+func WithTLS() Option {
+	return func(p *plugin) {
+		p.tls = true
+	}
+}
+
+// New returns a plugin serving gRPC on the provided address. The server is constructed during initialization and
+// attached to the application context (see FromContext), allowing the plugins following it to register their services
+// as they are initialized. The address is listened on during initialization (see Application.Listen), and the server
+// begins accepting connections when the plugin is started. The application is shutdown should the server fail while
+// serving. When the application is shutdown, the server is drained (see lifecycle.Drainer) before any plugin is
+// shutdown, stopping gracefully while waiting for in-flight RPCs to complete. Should the drain timeout (see
+// WithDrainTimeout) be exceeded, the server is stopped forcefully.
+func New(addr string, opts ...Option) lifecycle.Plugin {
+	p := &plugin{
+		name:         DefaultName,
+		addr:         addr,
+		drainTimeout: DefaultDrainTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	name         string
+	addr         string
+	drainTimeout time.Duration
+	serverOpts   []grpc.ServerOption
+	tls          bool
+
+	listener net.Listener
+	server   *grpc.Server
+	exited   chan error
+}
+
+func (p *plugin) Name() string {
+	return p.name
+}
+
+func (p *plugin) Dependencies() []string {
+	if p.tls {
+		return []string{tlsplugin.Name}
+	}
+	return nil
+}
+
+func (p *plugin) InitializeContext(ctx context.Context, app *lifecycle.Application) error {
+	opts := p.serverOpts
+	if p.tls {
+		config, ok := tlsplugin.FromContext(ctx)
+		if !ok {
+			return errors.New("tls config not found, is the tls plugin registered?")
+		}
+		opts = append(opts[:len(opts):len(opts)], grpc.Creds(credentials.NewTLS(config)))
+	}
+
+	listener, err := app.Listen("tcp", p.addr)
+	if err != nil {
+		return err
+	}
+
+	p.listener = listener
+	p.server = grpc.NewServer(opts...)
+
+	app.WithValue(contextKey{p.name}, p.server)
+	return nil
+}
+
+func (p *plugin) RunContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) StartContext(_ context.Context, _ *lifecycle.Application) error {
+	p.exited = make(chan error, 1)
+	go func() {
+		p.exited <- p.server.Serve(p.listener)
+	}()
+	return nil
+}
+
+// Drain stops the server gracefully, waiting for in-flight RPCs to complete, and stops the server forcefully once the
+// drain timeout is exceeded.
+func (p *plugin) Drain(ctx context.Context, _ *lifecycle.Application) error {
+	if p.server == nil {
+		return nil
+	}
+
+	if p.drainTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.drainTimeout)
+		defer cancel()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		p.server.GracefulStop()
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		p.server.Stop()
+		<-stopped
+		return fmt.Errorf("stopped forcefully: %w", ctx.Err())
+	}
+}
+
+// ShutdownContext drains the server when it has yet to be drained (for example, when the plugin is restarted).
+func (p *plugin) ShutdownContext(ctx context.Context, app *lifecycle.Application) error {
+	return p.Drain(ctx, app)
+}
+
+func (p *plugin) Exited() <-chan error {
+	return p.exited
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	return p.InitializeContext(app.Context(), app)
+}
+
+func (p *plugin) Start(app *lifecycle.Application) error {
+	return p.StartContext(app.Context(), app)
+}
+
+func (p *plugin) Shutdown(app *lifecycle.Application) error {
+	return p.ShutdownContext(app.Context(), app)
+}
+
+var _ lifecycle.PluginContext = &plugin{}
+var _ lifecycle.Exiter = &plugin{}
+var _ lifecycle.Dependent = &plugin{}
+var _ lifecycle.Drainer = &plugin{}
+
+// FromContext returns the server attached to the provided context by the plugin with the provided name (DefaultName
+// unless configured using WithName).
+func FromContext(ctx context.Context, name string) (*grpc.Server, bool) {
+	server, ok := ctx.Value(contextKey{name}).(*grpc.Server)
+	return server, ok
+}
@@ -0,0 +1,63 @@
+package grpcplugin
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+func Test_Plugin(t *testing.T) {
+	// reserve a free port for the plugin to listen on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	var status grpc_health_v1.HealthCheckResponse_ServingStatus
+	var checkErr error
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(
+		New(addr),
+		&lifecycle.PluginFuncs{
+			InitializeFunc: func(app *lifecycle.Application) error {
+				server, ok := FromContext(app.Context(), DefaultName)
+				require.True(t, ok, "server not attached")
+
+				grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+				return nil
+			},
+		},
+	)
+
+	go func() {
+		<-app.Ready()
+		defer app.Shutdown(nil)
+
+		conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			checkErr = err
+			return
+		}
+		defer conn.Close()
+
+		resp, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		if err != nil {
+			checkErr = err
+			return
+		}
+		status = resp.GetStatus()
+	}()
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, checkErr)
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, status)
+}
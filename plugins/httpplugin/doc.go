@@ -0,0 +1,3 @@
+// Package httpplugin provides a plugin managing an http.Server, draining in-flight requests when the application is
+// shutdown.
+package httpplugin
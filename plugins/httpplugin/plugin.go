@@ -0,0 +1,230 @@
+package httpplugin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/effxhq/go-lifecycle"
+	"github.com/effxhq/go-lifecycle/plugins/tlsplugin"
+)
+
+const (
+	// DefaultName is the name of the plugin when no name is configured.
+	DefaultName = "http"
+	// DefaultDrainTimeout is how long in-flight requests are given to complete during shutdown when no drain timeout
+	// is configured.
+	DefaultDrainTimeout = 30 * time.Second
+	// DefaultReadHeaderTimeout bounds how long the server waits on the headers of a request when the server does not
+	// configure a timeout of its own.
+	DefaultReadHeaderTimeout = 10 * time.Second
+)
+
+type contextKey struct {
+	name string
+}
+
+func (k contextKey) String() string {
+	return "http server " + k.name
+}
+
+// Option configures the plugin.
+type Option func(p *plugin)
+
+// WithName configures the name of the plugin, allowing several servers to be registered with the same application.
+// The server is attached to the application context under this name (see FromContext).
+func WithName(name string) Option {
+	return func(p *plugin) {
+		p.name = name
+	}
+}
+
+// WithDrainTimeout configures how long in-flight requests are given to complete during shutdown. Once exceeded,
+// remaining connections are closed.
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(p *plugin) {
+		p.drainTimeout = timeout
+	}
+}
+
+// WithServer configures the server before it is started, allowing timeouts and other settings to be customized.
+func WithServer(configure func(server *http.Server)) Option {
+	return func(p *plugin) {
+		p.configure = append(p.configure, configure)
+	}
+}
+
+// WithListener wraps the listener the server accepts connections from (for example, to track connections using the
+// connplugin package). The function is invoked each time the plugin is initialized.
+func WithListener(wrap func(listener net.Listener) net.Listener) Option {
+	return func(p *plugin) {
+		p.wrap = append(p.wrap, wrap)
+	}
+}
+
+// WithTLS serves HTTPS using the tls.Config provided by the tlsplugin package, whose plugin must also be registered.
+// The certificate served is reloaded as it is rotated.
+func WithTLS() Option {
+	return func(p *plugin) {
+		p.tls = true
+	}
+}
+
+// New returns a plugin serving the provided handler on the provided address. The server is constructed during
+// initialization and attached to the application context (see FromContext), allowing other plugins to customize it
+// before it is started. The address is listened on during initialization (see Application.Listen), so port conflicts
+// fail the application before any plugin is started. The server begins accepting connections when the plugin is
+// started, and the application is shutdown should the server fail while serving. When the application is shutdown, the
+// server is drained (see lifecycle.Drainer) before any plugin is shutdown: it stops accepting connections and in-flight
+// requests are given the drain timeout (see WithDrainTimeout) to complete.
+func New(addr string, handler http.Handler, opts ...Option) lifecycle.Plugin {
+	p := &plugin{
+		name:         DefaultName,
+		addr:         addr,
+		handler:      handler,
+		drainTimeout: DefaultDrainTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	name         string
+	addr         string
+	handler      http.Handler
+	drainTimeout time.Duration
+	configure    []func(server *http.Server)
+	wrap         []func(listener net.Listener) net.Listener
+	tls          bool
+
+	listener net.Listener
+	server   *http.Server
+	exited   chan error
+}
+
+func (p *plugin) Name() string {
+	return p.name
+}
+
+func (p *plugin) Dependencies() []string {
+	if p.tls {
+		return []string{tlsplugin.Name}
+	}
+	return nil
+}
+
+func (p *plugin) InitializeContext(ctx context.Context, app *lifecycle.Application) error {
+	listener, err := app.Listen("tcp", p.addr)
+	if err != nil {
+		return err
+	}
+
+	for _, wrap := range p.wrap {
+		listener = wrap(listener)
+	}
+
+	p.listener = listener
+	p.server = &http.Server{
+		Addr:              p.addr,
+		Handler:           p.handler,
+		ReadHeaderTimeout: DefaultReadHeaderTimeout,
+		BaseContext: func(net.Listener) context.Context {
+			return app.Context()
+		},
+	}
+
+	if p.tls {
+		config, ok := tlsplugin.FromContext(ctx)
+		if !ok {
+			return errors.New("tls config not found, is the tls plugin registered?")
+		}
+		p.server.TLSConfig = config
+	}
+
+	for _, configure := range p.configure {
+		configure(p.server)
+	}
+
+	app.WithValue(contextKey{p.name}, p.server)
+	return nil
+}
+
+func (p *plugin) RunContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) StartContext(_ context.Context, _ *lifecycle.Application) error {
+	p.exited = make(chan error, 1)
+	go func() {
+		var err error
+		if p.server.TLSConfig != nil {
+			err = p.server.ServeTLS(p.listener, "", "")
+		} else {
+			err = p.server.Serve(p.listener)
+		}
+
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+		p.exited <- err
+	}()
+	return nil
+}
+
+// Drain stops accepting connections and waits for in-flight requests to complete, closing the remaining connections
+// once the drain timeout is exceeded.
+func (p *plugin) Drain(ctx context.Context, _ *lifecycle.Application) error {
+	if p.exited == nil {
+		return nil
+	}
+
+	if p.drainTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.drainTimeout)
+		defer cancel()
+	}
+
+	if err := p.server.Shutdown(ctx); err != nil {
+		return errors.Join(err, p.server.Close())
+	}
+	return nil
+}
+
+// ShutdownContext drains the server when it has yet to be drained (for example, when the plugin is restarted).
+func (p *plugin) ShutdownContext(ctx context.Context, app *lifecycle.Application) error {
+	return p.Drain(ctx, app)
+}
+
+func (p *plugin) Exited() <-chan error {
+	return p.exited
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	return p.InitializeContext(app.Context(), app)
+}
+
+func (p *plugin) Start(app *lifecycle.Application) error {
+	return p.StartContext(app.Context(), app)
+}
+
+func (p *plugin) Shutdown(app *lifecycle.Application) error {
+	return p.ShutdownContext(app.Context(), app)
+}
+
+var _ lifecycle.PluginContext = &plugin{}
+var _ lifecycle.Exiter = &plugin{}
+var _ lifecycle.Dependent = &plugin{}
+var _ lifecycle.Drainer = &plugin{}
+
+// FromContext returns the server attached to the provided context by the plugin with the provided name (DefaultName
+// unless configured using WithName).
+func FromContext(ctx context.Context, name string) (*http.Server, bool) {
+	server, ok := ctx.Value(contextKey{name}).(*http.Server)
+	return server, ok
+}
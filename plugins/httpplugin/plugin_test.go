@@ -0,0 +1,87 @@
+package httpplugin
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// freeAddr returns an address that's available to listen on.
+func freeAddr(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	return listener.Addr().String()
+}
+
+func Test_Plugin(t *testing.T) {
+	addr := freeAddr(t)
+
+	received := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		// the request is still in-flight once shutdown begins
+		time.Sleep(50 * time.Millisecond)
+		_, _ = io.WriteString(w, "hello")
+	})
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(New(addr, handler, WithServer(func(server *http.Server) {
+		server.WriteTimeout = time.Second
+	})))
+
+	server, ok := FromContext(app.Context(), DefaultName)
+	require.True(t, ok, "server not attached")
+	require.Equal(t, time.Second, server.WriteTimeout)
+
+	var body string
+	var reqErr error
+
+	requested := make(chan struct{})
+	go func() {
+		defer close(requested)
+
+		<-app.Ready()
+		go func() {
+			<-received
+			app.Shutdown(nil)
+		}()
+
+		resp, err := http.Get("http://" + addr)
+		if err != nil {
+			reqErr = err
+			return
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		body, reqErr = string(data), err
+	}()
+
+	require.NoError(t, app.StartE())
+	<-requested
+
+	require.NoError(t, reqErr)
+	require.Equal(t, "hello", body)
+}
+
+func Test_Plugin_AddressInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(New(listener.Addr().String(), http.NotFoundHandler(), WithName("api")))
+
+	var pluginErr *lifecycle.PluginError
+	require.ErrorAs(t, app.StartE(), &pluginErr)
+	require.Equal(t, "api (*httpplugin.plugin)", pluginErr.Plugin)
+	require.Equal(t, lifecycle.PhaseInitialize, pluginErr.Phase)
+}
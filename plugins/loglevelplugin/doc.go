@@ -0,0 +1,4 @@
+// Package loglevelplugin provides a plugin that toggles the level of a logger between info and debug when the
+// application receives a signal (SIGUSR2 by default). This allows live incidents to be debugged without redeploying.
+// Levels can be adapted from slog, zap (including loggers constructed using zapplugin), and logrus.
+package loglevelplugin
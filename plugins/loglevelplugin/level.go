@@ -0,0 +1,76 @@
+package loglevelplugin
+
+import (
+	"log/slog"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Level is the level of a logger that can be toggled between info and debug.
+type Level interface {
+	// Debug returns true when debug logs are enabled.
+	Debug() bool
+	// SetDebug enables or disables debug logs.
+	SetDebug(debug bool)
+}
+
+// Slog adapts the provided slog.LevelVar, which should be used as the level of the handler provided to
+// lifecycle.WithLogger.
+func Slog(level *slog.LevelVar) Level {
+	return slogLevel{level}
+}
+
+type slogLevel struct {
+	level *slog.LevelVar
+}
+
+func (l slogLevel) Debug() bool {
+	return l.level.Level() <= slog.LevelDebug
+}
+
+func (l slogLevel) SetDebug(debug bool) {
+	l.level.Set(choose(debug, slog.LevelDebug, slog.LevelInfo))
+}
+
+// Zap adapts the provided zap.AtomicLevel, such as the Level of the zap.Config provided to zapplugin.
+func Zap(level zap.AtomicLevel) Level {
+	return zapLevel{level}
+}
+
+type zapLevel struct {
+	level zap.AtomicLevel
+}
+
+func (l zapLevel) Debug() bool {
+	return l.level.Enabled(zapcore.DebugLevel)
+}
+
+func (l zapLevel) SetDebug(debug bool) {
+	l.level.SetLevel(choose(debug, zapcore.DebugLevel, zapcore.InfoLevel))
+}
+
+// Logrus adapts the level of the provided logrus.Logger, such as one constructed using logrusplugin.
+func Logrus(logger *logrus.Logger) Level {
+	return logrusLevel{logger}
+}
+
+type logrusLevel struct {
+	logger *logrus.Logger
+}
+
+func (l logrusLevel) Debug() bool {
+	return l.logger.IsLevelEnabled(logrus.DebugLevel)
+}
+
+func (l logrusLevel) SetDebug(debug bool) {
+	l.logger.SetLevel(choose(debug, logrus.DebugLevel, logrus.InfoLevel))
+}
+
+func choose[T any](condition bool, yes, no T) T {
+	if condition {
+		return yes
+	}
+	return no
+}
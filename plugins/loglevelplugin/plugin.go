@@ -0,0 +1,54 @@
+package loglevelplugin
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// Plugin returns a plugin that toggles the provided levels between info and debug each time the application receives
+// DefaultSignal.
+func Plugin(levels ...Level) lifecycle.Plugin {
+	return PluginOnSignal(DefaultSignal, levels...)
+}
+
+// PluginOnSignal returns a plugin that toggles the provided levels between info and debug each time the application
+// receives the provided signal. When the signal is nil, levels are never toggled.
+func PluginOnSignal(sig os.Signal, levels ...Level) lifecycle.Plugin {
+	return &plugin{
+		signal: sig,
+		levels: levels,
+	}
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	signal os.Signal
+	levels []Level
+}
+
+func (p *plugin) Name() string {
+	return "log-level"
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	if p.signal != nil {
+		app.HandleSignal(p.signal, p.toggle)
+	}
+	return nil
+}
+
+// toggle enables debug logs unless every level already has them enabled, in which case they are disabled.
+func (p *plugin) toggle(app *lifecycle.Application) {
+	debug := false
+	for _, level := range p.levels {
+		debug = debug || !level.Debug()
+	}
+
+	for _, level := range p.levels {
+		level.SetDebug(debug)
+	}
+
+	app.Logger().Info("toggled log level", slog.Bool("debug", debug))
+}
@@ -0,0 +1,81 @@
+//go:build !windows
+// +build !windows
+
+package loglevelplugin
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+func Test_Plugin(t *testing.T) {
+	app := lifecycle.NewApplication(
+		lifecycle.WithLogger(slog.New(slog.NewTextHandler(&strings.Builder{}, nil))),
+		lifecycle.WithTerminator(func(err error) {}),
+	)
+
+	slogLevel := &slog.LevelVar{}
+	zapLevel := zap.NewAtomicLevel()
+	logger := logrus.New()
+
+	levels := []Level{Slog(slogLevel), Zap(zapLevel), Logrus(logger)}
+	app.Initialize(Plugin(levels...))
+
+	for _, debug := range []bool{true, false} {
+		require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR2))
+
+		for _, level := range levels {
+			require.Eventually(t, func() bool {
+				return level.Debug() == debug
+			}, time.Second, time.Millisecond)
+		}
+	}
+
+	require.NoError(t, app.RunE())
+}
+
+func Test_Plugin_Restart(t *testing.T) {
+	app := lifecycle.NewApplication(
+		lifecycle.WithLogger(slog.New(slog.NewTextHandler(&strings.Builder{}, nil))),
+		lifecycle.WithTerminator(func(err error) {}),
+	)
+
+	level := Slog(&slog.LevelVar{})
+	app.Initialize(Plugin(level))
+
+	toggled := make(chan error, 1)
+	app.AfterStart(func(_ context.Context) error {
+		go func() {
+			defer app.Shutdown(nil)
+
+			if err := app.Restart(); err != nil {
+				toggled <- err
+				return
+			}
+			toggled <- syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+
+			// give the handlers the chance to toggle the level before shutting down
+			deadline := time.Now().Add(time.Second)
+			for !level.Debug() && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}()
+		return nil
+	})
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, <-toggled)
+	// the level is toggled once, rather than once for each time the plugin was initialized
+	require.True(t, level.Debug(), "debug logs toggled more than once")
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+// +build !windows
+
+package loglevelplugin
+
+import (
+	"syscall"
+)
+
+// DefaultSignal is the signal that toggles the level when no signal is provided.
+var DefaultSignal = syscall.SIGUSR2
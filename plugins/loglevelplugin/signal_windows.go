@@ -0,0 +1,12 @@
+//go:build windows
+// +build windows
+
+package loglevelplugin
+
+import (
+	"os"
+)
+
+// DefaultSignal is the signal that toggles the level when no signal is provided. Windows does not support SIGUSR2, so
+// levels are only toggled when a signal is provided.
+var DefaultSignal os.Signal
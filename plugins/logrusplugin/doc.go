@@ -0,0 +1,3 @@
+// Package logrusplugin provides a plugin that constructs a logrus logger, attaches it to the application context, and
+// flushes its output when the application is shutdown.
+package logrusplugin
@@ -0,0 +1,88 @@
+package logrusplugin
+
+import (
+	"context"
+	"errors"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+type contextKey struct{}
+
+func (contextKey) String() string {
+	return "logrus logger"
+}
+
+// Plugin returns a plugin that constructs a logger during initialization and attaches it to the application context.
+// The provided function may configure the logger (such as its level, formatter, and output) before it's attached. When
+// the output of the logger can be synced (such as an *os.File), it's synced when the application is shutdown.
+func Plugin(configure func(logger *logrus.Logger) error) lifecycle.Plugin {
+	return &plugin{
+		configure: configure,
+	}
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	configure func(logger *logrus.Logger) error
+	logger    *logrus.Logger
+}
+
+func (p *plugin) Name() string {
+	return "logrus"
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	logger := logrus.New()
+	if p.configure != nil {
+		if err := p.configure(logger); err != nil {
+			return err
+		}
+	}
+
+	p.logger = logger
+	app.WithValue(contextKey{}, logger)
+	return nil
+}
+
+type syncer interface {
+	Sync() error
+}
+
+func (p *plugin) Shutdown(_ *lifecycle.Application) error {
+	if p.logger == nil {
+		return nil
+	}
+
+	out, ok := p.logger.Out.(syncer)
+	if !ok {
+		return nil
+	}
+	return ignoreUnsyncable(out.Sync())
+}
+
+// FromContext returns the logger attached to the provided context.
+func FromContext(ctx context.Context) (*logrus.Logger, bool) {
+	logger, ok := ctx.Value(contextKey{}).(*logrus.Logger)
+	return logger, ok
+}
+
+// Logger returns the logger attached to the application context. Should no logger be attached, the standard logger is
+// returned.
+func Logger(app *lifecycle.Application) *logrus.Logger {
+	if logger, ok := FromContext(app.Context()); ok {
+		return logger
+	}
+	return logrus.StandardLogger()
+}
+
+// ignoreUnsyncable drops the errors returned when syncing outputs that cannot be synced, such as terminals.
+func ignoreUnsyncable(err error) error {
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,43 @@
+package logrusplugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+func Test_Plugin(t *testing.T) {
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+
+	out, err := os.Create(filepath.Join(t.TempDir(), "app.log"))
+	require.NoError(t, err)
+	defer out.Close()
+
+	app.Initialize(Plugin(func(logger *logrus.Logger) error {
+		logger.SetOutput(out)
+		logger.SetLevel(logrus.DebugLevel)
+		return nil
+	}))
+
+	logger, ok := FromContext(app.Context())
+	require.True(t, ok, "logger not attached")
+	require.Equal(t, logrus.DebugLevel, Logger(app).GetLevel())
+
+	logger.Info("started")
+	require.NoError(t, app.RunE())
+
+	contents, err := os.ReadFile(out.Name())
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "msg=started")
+}
+
+func Test_Logger_Missing(t *testing.T) {
+	app := lifecycle.NewApplication()
+
+	require.Equal(t, logrus.StandardLogger(), Logger(app))
+}
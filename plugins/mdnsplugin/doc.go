@@ -0,0 +1,3 @@
+// Package mdnsplugin provides a plugin advertising the application over mDNS (DNS-SD) while it is running, allowing LAN
+// tooling to discover it without a registry.
+package mdnsplugin
@@ -0,0 +1,130 @@
+package mdnsplugin
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/grandcat/zeroconf"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// DefaultDomain is the domain the service is advertised in when no domain is configured.
+const DefaultDomain = "local."
+
+// Option configures the plugin.
+type Option func(p *plugin)
+
+// WithDomain configures the domain the service is advertised in.
+func WithDomain(domain string) Option {
+	return func(p *plugin) {
+		p.domain = domain
+	}
+}
+
+// WithText configures the TXT records advertised alongside the service (such as "version=1.2.3").
+func WithText(txt ...string) Option {
+	return func(p *plugin) {
+		p.txt = append(p.txt, txt...)
+	}
+}
+
+// WithInterfaces configures the network interfaces the service is advertised on. By default, the service is
+// advertised on every multicast interface.
+func WithInterfaces(ifaces ...net.Interface) Option {
+	return func(p *plugin) {
+		p.ifaces = append(p.ifaces, ifaces...)
+	}
+}
+
+// Plugin returns a plugin advertising the named instance of the provided service (such as "_http._tcp") on the
+// provided port once the application has started and is ready (see Application.Ready). The advertisement is withdrawn
+// as soon as the application begins shutting down, notifying browsers that the instance is going away.
+func Plugin(instance, service string, port int, opts ...Option) lifecycle.Plugin {
+	p := &plugin{
+		instance: instance,
+		service:  service,
+		port:     port,
+		domain:   DefaultDomain,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	instance string
+	service  string
+	port     int
+	domain   string
+	txt      []string
+	ifaces   []net.Interface
+
+	mu     sync.Mutex
+	server *zeroconf.Server
+}
+
+func (p *plugin) Name() string {
+	return "mdns"
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	app.OnStateChange(func(_, to lifecycle.State) {
+		if to == lifecycle.StateShutdown {
+			p.withdraw()
+		}
+	})
+	return nil
+}
+
+func (p *plugin) Start(app *lifecycle.Application) error {
+	app.Go(func(ctx context.Context) error {
+		select {
+		case <-app.Ready():
+			return p.advertise(app)
+		case <-ctx.Done():
+			return nil
+		}
+	})
+	return nil
+}
+
+func (p *plugin) Shutdown(_ *lifecycle.Application) error {
+	p.withdraw()
+	return nil
+}
+
+// advertise registers the service, unless the application has begun shutting down.
+func (p *plugin) advertise(app *lifecycle.Application) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if app.State() >= lifecycle.StateShutdown {
+		return nil
+	}
+
+	server, err := zeroconf.Register(p.instance, p.service, p.domain, p.port, p.txt, p.ifaces)
+	if err != nil {
+		return err
+	}
+
+	p.server = server
+	return nil
+}
+
+// withdraw stops advertising the service, sending goodbye packets so browsers forget the instance.
+func (p *plugin) withdraw() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.server == nil {
+		return
+	}
+
+	p.server.Shutdown()
+	p.server = nil
+}
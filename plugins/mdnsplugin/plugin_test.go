@@ -0,0 +1,51 @@
+package mdnsplugin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grandcat/zeroconf"
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+func Test_Plugin(t *testing.T) {
+	var found *zeroconf.ServiceEntry
+	var browseErr error
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(Plugin("lifecycle-test", "_lifecycle-test._tcp", 8080, WithText("version=1.2.3")))
+
+	go func() {
+		<-app.Ready()
+		defer app.Shutdown(nil)
+
+		resolver, err := zeroconf.NewResolver(nil)
+		if err != nil {
+			browseErr = err
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		entries := make(chan *zeroconf.ServiceEntry)
+		if err := resolver.Lookup(ctx, "lifecycle-test", "_lifecycle-test._tcp", DefaultDomain, entries); err != nil {
+			browseErr = err
+			return
+		}
+
+		select {
+		case found = <-entries:
+		case <-ctx.Done():
+			browseErr = ctx.Err()
+		}
+	}()
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, browseErr)
+	require.Equal(t, 8080, found.Port)
+	require.Equal(t, []string{"version=1.2.3"}, found.Text)
+}
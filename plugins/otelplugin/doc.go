@@ -0,0 +1,5 @@
+// Package otelplugin reports the lifecycle of a lifecycle.Application to OpenTelemetry. The boot of the application is
+// traced using a root span, with a child span for each phase and a span for each plugin beneath the phase it ran in.
+// Shutting down the application is traced separately, as are phases (such as reloads) that occur once booted. The
+// package also provides a plugin managing the lifecycle of the OpenTelemetry SDK providers.
+package otelplugin
@@ -0,0 +1,184 @@
+package otelplugin
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+const (
+	// BootSpan is the name of the root span covering the boot of the application.
+	BootSpan = "lifecycle.boot"
+
+	// PhaseKey is the attribute identifying the phase of the lifecycle a span covers.
+	PhaseKey = attribute.Key("lifecycle.phase")
+	// PluginKey is the attribute identifying the plugin a span covers.
+	PluginKey = attribute.Key("lifecycle.plugin")
+)
+
+// Hook returns a lifecycle.Hook that records spans using the provided tracer. The hook should be configured before the
+// application is initialized (for example, using lifecycle.WithHook) so the entire boot is traced.
+func Hook(tracer trace.Tracer) lifecycle.Hook {
+	t := &tracing{
+		tracer: tracer,
+		phases: make(map[lifecycle.Phase]span, 0),
+	}
+	return t.handle
+}
+
+type span struct {
+	ctx  context.Context
+	span trace.Span
+}
+
+type tracing struct {
+	mu     sync.Mutex
+	tracer trace.Tracer
+
+	// root is the span phases are recorded beneath. Once booted, each phase is recorded as its own root span.
+	root   *span
+	booted bool
+
+	// phases holds the spans of the phases that have been entered but not yet exited, in the order they were entered
+	phases map[lifecycle.Phase]span
+	open   []lifecycle.Phase
+}
+
+func (t *tracing) handle(event lifecycle.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch event.Kind {
+	case lifecycle.EventPhaseEnter:
+		t.enter(event)
+	case lifecycle.EventPhaseExit:
+		t.exit(event)
+	case lifecycle.EventPlugin:
+		t.plugin(event)
+	case lifecycle.EventWarning:
+		t.warning(event)
+	}
+}
+
+func (t *tracing) enter(event lifecycle.Event) {
+	switch event.Phase {
+	case lifecycle.PhaseShutdown:
+		t.endAll(event.Time)
+		t.booted = true
+	case lifecycle.PhaseTerminated:
+		t.endAll(event.Time)
+		return
+	}
+
+	if t.root == nil && !t.booted {
+		ctx, root := t.tracer.Start(context.Background(), BootSpan, trace.WithTimestamp(event.StartedAt))
+		t.root = &span{ctx: ctx, span: root}
+	}
+
+	// phases entered during another phase (such as starting plugins during a restart) are nested beneath it
+	ctx, s := t.tracer.Start(t.parent(""), "lifecycle."+string(event.Phase),
+		trace.WithTimestamp(event.StartedAt),
+		trace.WithAttributes(PhaseKey.String(string(event.Phase))),
+	)
+	t.phases[event.Phase] = span{ctx: ctx, span: s}
+	t.open = append(t.open, event.Phase)
+}
+
+// parent returns the context spans are started beneath, preferring the span of the provided phase when it's open.
+func (t *tracing) parent(phase lifecycle.Phase) context.Context {
+	if s, ok := t.phases[phase]; ok {
+		return s.ctx
+	}
+
+	if len(t.open) > 0 {
+		return t.phases[t.open[len(t.open)-1]].ctx
+	}
+
+	if t.root != nil {
+		return t.root.ctx
+	}
+	return context.Background()
+}
+
+func (t *tracing) exit(event lifecycle.Event) {
+	if s, ok := t.phases[event.Phase]; ok {
+		recordError(s.span, event.Err)
+		s.span.End(trace.WithTimestamp(event.Time))
+		t.close(event.Phase)
+	}
+
+	// the application has booted once it has been run or started
+	if t.root != nil && (event.Phase == lifecycle.PhaseRun || event.Phase == lifecycle.PhaseStart) {
+		t.endAll(event.Time)
+		t.booted = true
+	}
+}
+
+func (t *tracing) plugin(event lifecycle.Event) {
+	// the phase is aborted when a plugin fails
+	if s, ok := t.phases[event.Phase]; ok {
+		recordError(s.span, event.Err)
+	}
+
+	_, s := t.tracer.Start(t.parent(event.Phase), string(event.Phase)+" "+event.Plugin,
+		trace.WithTimestamp(event.StartedAt),
+		trace.WithAttributes(PhaseKey.String(string(event.Phase)), PluginKey.String(event.Plugin)),
+	)
+
+	recordError(s, event.Err)
+	s.End(trace.WithTimestamp(event.StartedAt.Add(event.Duration)))
+}
+
+func (t *tracing) warning(event lifecycle.Event) {
+	s := trace.SpanFromContext(t.parent(event.Phase))
+
+	attributes := []attribute.KeyValue{PhaseKey.String(string(event.Phase))}
+	if event.Plugin != "" {
+		attributes = append(attributes, PluginKey.String(event.Plugin))
+	}
+
+	s.AddEvent("lifecycle.warning", trace.WithTimestamp(event.Time), trace.WithAttributes(
+		append(attributes, attribute.String("exception.message", event.Err.Error()))...,
+	))
+}
+
+// close forgets the span of the provided phase.
+func (t *tracing) close(phase lifecycle.Phase) {
+	delete(t.phases, phase)
+
+	for i, open := range t.open {
+		if open == phase {
+			t.open = append(t.open[:i], t.open[i+1:]...)
+			break
+		}
+	}
+}
+
+// endAll ends each of the spans that remain open, such as phases that were aborted.
+func (t *tracing) endAll(at time.Time) {
+	for len(t.open) > 0 {
+		phase := t.open[len(t.open)-1]
+		t.phases[phase].span.End(trace.WithTimestamp(at))
+		t.close(phase)
+	}
+
+	if t.root != nil {
+		t.root.span.End(trace.WithTimestamp(at))
+		t.root = nil
+	}
+}
+
+func recordError(s trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	s.RecordError(err)
+	s.SetStatus(codes.Error, err.Error())
+}
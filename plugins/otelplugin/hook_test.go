@@ -0,0 +1,55 @@
+package otelplugin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+func Test_Hook(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	app := lifecycle.NewApplication(
+		lifecycle.WithHook(Hook(provider.Tracer("lifecycle"))),
+		lifecycle.WithTerminator(func(err error) {}),
+	)
+
+	app.Initialize(&lifecycle.PluginFuncs{
+		StartFunc: func(app *lifecycle.Application) error {
+			return fmt.Errorf("address already in use")
+		},
+	})
+
+	require.Error(t, app.StartE())
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+
+	boot := spans[BootSpan]
+	require.NotNil(t, boot, "boot span not recorded")
+	require.False(t, boot.Parent().IsValid(), "boot span is not a root span")
+
+	start := spans["lifecycle.start"]
+	require.NotNil(t, start, "start span not recorded")
+	require.Equal(t, boot.SpanContext().SpanID(), start.Parent().SpanID())
+	require.Equal(t, codes.Error, start.Status().Code)
+
+	plugin := spans["start plugin[0] (*lifecycle.PluginFuncs)"]
+	require.NotNil(t, plugin, "plugin span not recorded")
+	require.Equal(t, start.SpanContext().SpanID(), plugin.Parent().SpanID())
+	require.Contains(t, plugin.Attributes(), PluginKey.String("plugin[0] (*lifecycle.PluginFuncs)"))
+
+	shutdown := spans["lifecycle.shutdown"]
+	require.NotNil(t, shutdown, "shutdown span not recorded")
+	require.False(t, shutdown.Parent().IsValid(), "shutdown span is not a root span")
+	require.NotEqual(t, boot.SpanContext().TraceID(), shutdown.SpanContext().TraceID())
+}
@@ -0,0 +1,122 @@
+package otelplugin
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+type tracerProviderKey struct{}
+
+func (tracerProviderKey) String() string {
+	return "otel tracer provider"
+}
+
+type meterProviderKey struct{}
+
+func (meterProviderKey) String() string {
+	return "otel meter provider"
+}
+
+// Providers are the OpenTelemetry SDK providers managed by the plugin returned by ProviderPlugin. Either provider may
+// be nil.
+type Providers struct {
+	TracerProvider *sdktrace.TracerProvider
+	MeterProvider  *sdkmetric.MeterProvider
+}
+
+// ProviderPlugin returns a plugin that constructs the OpenTelemetry SDK providers during initialization, using the
+// provided function (which typically configures exporters). The providers are attached to the application context and
+// registered globally (see otel.SetTracerProvider and otel.SetMeterProvider). When the application is shutdown, the
+// providers are flushed and shutdown so telemetry is not dropped as the process exits.
+func ProviderPlugin(build func(ctx context.Context) (Providers, error)) lifecycle.Plugin {
+	return &providerPlugin{build: build}
+}
+
+type providerPlugin struct {
+	lifecycle.PluginFuncs
+	build     func(ctx context.Context) (Providers, error)
+	providers Providers
+}
+
+func (p *providerPlugin) Name() string {
+	return "otel"
+}
+
+func (p *providerPlugin) InitializeContext(ctx context.Context, app *lifecycle.Application) error {
+	providers, err := p.build(ctx)
+	if err != nil {
+		return err
+	}
+
+	p.providers = providers
+
+	if providers.TracerProvider != nil {
+		app.WithValue(tracerProviderKey{}, providers.TracerProvider)
+		otel.SetTracerProvider(providers.TracerProvider)
+	}
+
+	if providers.MeterProvider != nil {
+		app.WithValue(meterProviderKey{}, providers.MeterProvider)
+		otel.SetMeterProvider(providers.MeterProvider)
+	}
+
+	return nil
+}
+
+func (p *providerPlugin) RunContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *providerPlugin) StartContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *providerPlugin) ShutdownContext(ctx context.Context, _ *lifecycle.Application) error {
+	errs := make([]error, 0, 4)
+
+	if tp := p.providers.TracerProvider; tp != nil {
+		errs = append(errs, tp.ForceFlush(ctx), tp.Shutdown(ctx))
+	}
+
+	if mp := p.providers.MeterProvider; mp != nil {
+		errs = append(errs, mp.ForceFlush(ctx), mp.Shutdown(ctx))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *providerPlugin) Initialize(app *lifecycle.Application) error {
+	return p.InitializeContext(app.Context(), app)
+}
+
+func (p *providerPlugin) Shutdown(app *lifecycle.Application) error {
+	return p.ShutdownContext(app.Context(), app)
+}
+
+var _ lifecycle.PluginContext = &providerPlugin{}
+
+// TracerProvider returns the tracer provider attached to the provided context.
+func TracerProvider(ctx context.Context) (trace.TracerProvider, bool) {
+	tp, ok := ctx.Value(tracerProviderKey{}).(*sdktrace.TracerProvider)
+	if !ok {
+		return nil, false
+	}
+	return tp, true
+}
+
+// MeterProvider returns the meter provider attached to the provided context.
+func MeterProvider(ctx context.Context) (metric.MeterProvider, bool) {
+	mp, ok := ctx.Value(meterProviderKey{}).(*sdkmetric.MeterProvider)
+	if !ok {
+		return nil, false
+	}
+	return mp, true
+}
@@ -0,0 +1,72 @@
+package otelplugin
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// recordingExporter retains the exported spans once shutdown, unlike tracetest.InMemoryExporter.
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (e *recordingExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(_ context.Context) error {
+	return nil
+}
+
+func (e *recordingExporter) exported() []sdktrace.ReadOnlySpan {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.spans
+}
+
+func Test_ProviderPlugin(t *testing.T) {
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+
+	exporter := &recordingExporter{}
+	reader := sdkmetric.NewManualReader()
+
+	app.Initialize(
+		ProviderPlugin(func(ctx context.Context) (Providers, error) {
+			return Providers{
+				// batching ensures spans are only exported once flushed
+				TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter)),
+				MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+			}, nil
+		}),
+		&lifecycle.PluginFuncs{
+			RunFunc: func(app *lifecycle.Application) error {
+				tp, ok := TracerProvider(app.Context())
+				require.True(t, ok, "tracer provider not attached")
+
+				_, ok = MeterProvider(app.Context())
+				require.True(t, ok, "meter provider not attached")
+
+				_, span := tp.Tracer("test").Start(app.Context(), "work")
+				span.End()
+				return nil
+			},
+		},
+	)
+
+	require.NoError(t, app.RunE())
+	require.Len(t, exporter.exported(), 1)
+
+	_, ok := TracerProvider(context.Background())
+	require.False(t, ok)
+}
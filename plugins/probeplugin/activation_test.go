@@ -0,0 +1,69 @@
+//go:build !windows
+// +build !windows
+
+package probeplugin
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// activationAddrEnv provides the activated test process the address of the socket it was passed.
+const activationAddrEnv = "PROBEPLUGIN_TEST_ACTIVATION_ADDR"
+
+func Test_Plugin_Activated(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	f, err := listener.(*net.TCPListener).File()
+	require.NoError(t, err)
+	defer f.Close()
+
+	// like systemd, LISTEN_PID is set once the pid of the activated process is known
+	cmd := exec.Command("/bin/sh", "-c", `LISTEN_PID=$$ exec "$0" "$@"`,
+		os.Args[0], "-test.run=^Test_Plugin_Activated_Process$")
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	cmd.ExtraFiles = []*os.File{f}
+	cmd.Env = append(os.Environ(), "LISTEN_FDS=1", activationAddrEnv+"="+listener.Addr().String())
+	require.NoError(t, cmd.Start())
+
+	// the endpoints are served on the socket passed to the process rather than a socket of its own
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get("http://" + listener.Addr().String() + "/livez")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "ok\n", string(body))
+
+	require.NoError(t, cmd.Process.Signal(syscall.SIGTERM))
+	require.NoError(t, cmd.Wait())
+}
+
+// Test_Plugin_Activated_Process is run as the process started by Test_Plugin_Activated.
+func Test_Plugin_Activated_Process(t *testing.T) {
+	addr, ok := os.LookupEnv(activationAddrEnv)
+	if !ok {
+		t.Skip("only run as an activated process")
+	}
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	activated := app.Listeners()
+	require.Len(t, activated, 1)
+
+	app.Initialize(Plugin(WithAddr(addr)))
+	require.NoError(t, app.StartE())
+}
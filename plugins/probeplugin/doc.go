@@ -0,0 +1,3 @@
+// Package probeplugin provides a plugin serving liveness and readiness endpoints (such as those probed by Kubernetes)
+// backed by the health of the plugins and the lifecycle of the application.
+package probeplugin
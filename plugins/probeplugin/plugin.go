@@ -0,0 +1,269 @@
+package probeplugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+const (
+	// DefaultAddr is the address the endpoints are served on when no address is configured.
+	DefaultAddr = ":8081"
+	// DefaultLivenessPath is the path the liveness endpoint is served on when no path is configured.
+	DefaultLivenessPath = "/livez"
+	// DefaultReadinessPath is the path the readiness endpoint is served on when no path is configured.
+	DefaultReadinessPath = "/readyz"
+	// DefaultCheckTimeout bounds how long the readiness endpoint waits on the health checks of the plugins.
+	DefaultCheckTimeout = 5 * time.Second
+)
+
+type config struct {
+	addr          string
+	livenessPath  string
+	readinessPath string
+	versionPath   string
+	inspectPath   string
+	checkTimeout  time.Duration
+}
+
+// Option configures the endpoints.
+type Option func(c *config)
+
+// WithAddr configures the address the endpoints are served on.
+func WithAddr(addr string) Option {
+	return func(c *config) {
+		c.addr = addr
+	}
+}
+
+// WithLivenessPath configures the path the liveness endpoint is served on.
+func WithLivenessPath(path string) Option {
+	return func(c *config) {
+		c.livenessPath = path
+	}
+}
+
+// WithReadinessPath configures the path the readiness endpoint is served on.
+func WithReadinessPath(path string) Option {
+	return func(c *config) {
+		c.readinessPath = path
+	}
+}
+
+// WithVersionPath configures a path serving the metadata of the application (see Application.Metadata) as JSON, such as
+// "/version". By default, the metadata is not served.
+func WithVersionPath(path string) Option {
+	return func(c *config) {
+		c.versionPath = path
+	}
+}
+
+// WithIntrospectionPath configures a path serving a JSON document describing the application and each of its plugins
+// (see Introspection), such as "/debug/lifecycle". By default, the document is not served.
+func WithIntrospectionPath(path string) Option {
+	return func(c *config) {
+		c.inspectPath = path
+	}
+}
+
+// WithCheckTimeout configures how long the readiness endpoint waits on the health checks of the plugins.
+func WithCheckTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.checkTimeout = timeout
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{
+		addr:          DefaultAddr,
+		livenessPath:  DefaultLivenessPath,
+		readinessPath: DefaultReadinessPath,
+		checkTimeout:  DefaultCheckTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// Handler returns a handler serving the liveness and readiness endpoints of the provided application, allowing them to
+// be mounted on an existing server. The liveness endpoint succeeds until the application has terminated. The readiness
+// endpoint succeeds once the application is ready (see Application.Ready) and every plugin is healthy (see
+// Application.Health), and fails as soon as the application begins shutting down (see Application.Terminating) so
+// traffic is drained before its plugins are shutdown. When configured using WithVersionPath or WithIntrospectionPath,
+// the metadata of the application or the introspection document is also served.
+func Handler(app *lifecycle.Application, opts ...Option) http.Handler {
+	c := newConfig(opts)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(c.livenessPath, func(w http.ResponseWriter, r *http.Request) {
+		if app.State() >= lifecycle.StateTerminated {
+			respond(w, errors.New("application has terminated"))
+			return
+		}
+		respond(w, nil)
+	})
+	mux.HandleFunc(c.readinessPath, func(w http.ResponseWriter, r *http.Request) {
+		respond(w, ready(r.Context(), app, c.checkTimeout))
+	})
+
+	if c.versionPath != "" {
+		mux.HandleFunc(c.versionPath, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(app.Metadata())
+		})
+	}
+
+	if c.inspectPath != "" {
+		mux.HandleFunc(c.inspectPath, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(Inspect(app))
+		})
+	}
+	return mux
+}
+
+// Introspection describes a live application for debugging, served on the path configured using
+// WithIntrospectionPath.
+type Introspection struct {
+	lifecycle.Metadata
+	// State is the state of the application (for example, "started").
+	State string `json:"state"`
+	// Ready is true once every plugin is ready (see Application.Ready).
+	Ready bool `json:"ready"`
+	// Reason describes why the application was shutdown. Empty while the application is running.
+	Reason string `json:"reason,omitempty"`
+	// Plugins describes each registered plugin, including the phases it takes part in, how long it spent in each, and
+	// its current status.
+	Plugins []lifecycle.PluginStatus `json:"plugins"`
+}
+
+// Inspect returns the introspection document describing the provided application.
+func Inspect(app *lifecycle.Application) Introspection {
+	i := Introspection{
+		Metadata: app.Metadata(),
+		State:    app.State().String(),
+		Plugins:  app.PluginStatuses(),
+	}
+
+	select {
+	case <-app.Ready():
+		i.Ready = true
+	default:
+	}
+
+	if reason := app.ShutdownReason(); reason.Kind != lifecycle.ShutdownNone {
+		i.Reason = reason.String()
+	}
+	return i
+}
+
+// ready returns an error describing why the application is not ready to receive traffic.
+func ready(ctx context.Context, app *lifecycle.Application, timeout time.Duration) error {
+	select {
+	case <-app.Terminating():
+		return errors.New("application is shutting down")
+	default:
+	}
+
+	select {
+	case <-app.Ready():
+	default:
+		return errors.New("application is starting")
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return app.Health(ctx).Err()
+}
+
+func respond(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprintln(w, err)
+		return
+	}
+
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+// Plugin returns a plugin serving the liveness and readiness endpoints (see Handler). The endpoints are served as soon
+// as the plugin is initialized so probes are answered while the application is starting. The plugin should be
+// registered first so that the endpoints are served until every other plugin has been shutdown.
+func Plugin(opts ...Option) lifecycle.Plugin {
+	return &plugin{opts: opts}
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	opts   []Option
+	server *http.Server
+	served chan error
+}
+
+func (p *plugin) Name() string {
+	return "probes"
+}
+
+func (p *plugin) InitializeContext(_ context.Context, app *lifecycle.Application) error {
+	listener, err := app.Listen("tcp", newConfig(p.opts).addr)
+	if err != nil {
+		return err
+	}
+
+	p.server = &http.Server{
+		Handler:           Handler(app, p.opts...),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	p.served = make(chan error, 1)
+
+	go func() {
+		p.served <- p.server.Serve(listener)
+	}()
+	return nil
+}
+
+func (p *plugin) RunContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) StartContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) ShutdownContext(ctx context.Context, _ *lifecycle.Application) error {
+	if p.server == nil {
+		return nil
+	}
+
+	if err := p.server.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	if err := <-p.served; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	return p.InitializeContext(app.Context(), app)
+}
+
+func (p *plugin) Shutdown(app *lifecycle.Application) error {
+	return p.ShutdownContext(app.Context(), app)
+}
+
+var _ lifecycle.PluginContext = &plugin{}
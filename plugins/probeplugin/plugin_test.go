@@ -0,0 +1,164 @@
+package probeplugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+type healthPlugin struct {
+	lifecycle.PluginFuncs
+	err error
+}
+
+func (p *healthPlugin) Healthy(_ context.Context) error {
+	return p.err
+}
+
+func probe(handler http.Handler, path string) (int, string) {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder.Code, recorder.Body.String()
+}
+
+func Test_Handler(t *testing.T) {
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	handler := Handler(app, WithReadinessPath("/ready"))
+
+	var shuttingDown []string
+	health := &healthPlugin{}
+	app.Initialize(health, &lifecycle.PluginFuncs{
+		ShutdownFunc: func(app *lifecycle.Application) error {
+			code, body := probe(handler, "/ready")
+			live, _ := probe(handler, "/livez")
+			shuttingDown = []string{http.StatusText(code), body, http.StatusText(live)}
+			return nil
+		},
+	})
+
+	code, body := probe(handler, "/ready")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "application is starting\n", body)
+
+	var healthy, unhealthy []string
+	app.AfterStart(func(ctx context.Context) error {
+		go func() {
+			<-app.Ready()
+			defer app.Shutdown(nil)
+
+			code, body := probe(handler, "/ready")
+			healthy = []string{http.StatusText(code), body}
+
+			health.err = fmt.Errorf("connection refused")
+			code, body = probe(handler, "/ready")
+			unhealthy = []string{http.StatusText(code), body}
+		}()
+		return nil
+	})
+
+	require.NoError(t, app.StartE())
+	require.Equal(t, []string{"OK", "ok\n"}, healthy)
+	require.Equal(t, []string{"Service Unavailable", "plugin[0]: connection refused\n"}, unhealthy)
+	require.Equal(t, []string{"Service Unavailable", "application is shutting down\n", "OK"}, shuttingDown)
+
+	code, _ = probe(handler, "/livez")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+}
+
+func Test_Plugin(t *testing.T) {
+	// reserve a free port for the plugin to listen on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(
+		Plugin(WithAddr(addr), WithLivenessPath("/alive")),
+		&lifecycle.PluginFuncs{
+			RunFunc: func(app *lifecycle.Application) error {
+				resp, err := http.Get("http://" + addr + "/alive")
+				require.NoError(t, err)
+				defer resp.Body.Close()
+
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				require.Equal(t, "ok\n", string(body))
+				return nil
+			},
+		},
+	)
+
+	require.NoError(t, app.RunE())
+
+	_, err = http.Get("http://" + addr + "/alive")
+	require.Error(t, err, "server still listening")
+}
+
+func Test_Handler_Version(t *testing.T) {
+	app := lifecycle.NewApplication(
+		lifecycle.WithTerminator(func(err error) {}),
+		lifecycle.WithName("api"),
+		lifecycle.WithVersion("v1.2.3"),
+	)
+
+	code, _ := probe(Handler(app), "/version")
+	require.Equal(t, http.StatusNotFound, code)
+
+	code, body := probe(Handler(app, WithVersionPath("/version")), "/version")
+	require.Equal(t, http.StatusOK, code)
+
+	var metadata lifecycle.Metadata
+	require.NoError(t, json.Unmarshal([]byte(body), &metadata))
+	require.Equal(t, app.Metadata(), metadata)
+}
+
+func Test_Handler_Introspection(t *testing.T) {
+	app := lifecycle.NewApplication(
+		lifecycle.WithTerminator(func(err error) {}),
+		lifecycle.WithName("api"),
+	)
+
+	handler := Handler(app, WithIntrospectionPath("/debug/lifecycle"))
+
+	var running Introspection
+	app.Initialize(
+		&healthPlugin{},
+		&lifecycle.PluginFuncs{
+			RunFunc: func(app *lifecycle.Application) error {
+				code, body := probe(handler, "/debug/lifecycle")
+				require.Equal(t, http.StatusOK, code)
+				return json.Unmarshal([]byte(body), &running)
+			},
+		},
+	)
+
+	require.NoError(t, app.RunE())
+
+	require.Equal(t, "api", running.Name)
+	require.Equal(t, lifecycle.StateRunning.String(), running.State)
+	require.Empty(t, running.Reason)
+	require.Len(t, running.Plugins, 2)
+	require.Equal(t, "initialized", running.Plugins[0].Status)
+	require.Contains(t, running.Plugins[0].Phases, lifecycle.PhaseShutdown)
+	require.Contains(t, running.Plugins[0].Timings, lifecycle.PhaseInitialize)
+
+	code, body := probe(handler, "/debug/lifecycle")
+	require.Equal(t, http.StatusOK, code)
+
+	var terminated Introspection
+	require.NoError(t, json.Unmarshal([]byte(body), &terminated))
+	require.Equal(t, lifecycle.StateTerminated.String(), terminated.State)
+	require.Equal(t, lifecycle.ShutdownCompleted.String(), terminated.Reason)
+	require.Equal(t, "shutdown", terminated.Plugins[0].Status)
+}
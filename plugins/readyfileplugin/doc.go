@@ -0,0 +1,3 @@
+// Package readyfileplugin provides a plugin signaling readiness through the presence of a file, for environments using
+// exec or file based probes instead of HTTP.
+package readyfileplugin
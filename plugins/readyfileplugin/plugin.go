@@ -0,0 +1,72 @@
+package readyfileplugin
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// Plugin returns a plugin that creates a file at the provided path once the application has started and is ready (see
+// Application.Ready), and removes it as soon as the application begins shutting down. A file left behind by a previous
+// process is removed when the plugin is initialized.
+func Plugin(path string) lifecycle.Plugin {
+	return &plugin{path: path}
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	path string
+}
+
+func (p *plugin) Name() string {
+	return "readyfile"
+}
+
+func (p *plugin) Initialize(_ *lifecycle.Application) error {
+	return p.remove()
+}
+
+func (p *plugin) Start(app *lifecycle.Application) error {
+	app.Go(func(ctx context.Context) error {
+		select {
+		case <-app.Ready():
+		case <-ctx.Done():
+			return nil
+		}
+
+		if err := os.WriteFile(p.path, nil, 0o644); err != nil {
+			return err
+		}
+
+		select {
+		case <-app.Terminating():
+		case <-ctx.Done():
+		}
+
+		// the file is removed ahead of any pre-shutdown delay (see lifecycle.WithPreShutdownDelay), while restarts
+		// leave it to Shutdown
+		select {
+		case <-app.Terminating():
+			if err := p.remove(); err != nil {
+				app.Logger().Error("failed to remove readiness file", "path", p.path, "error", err)
+			}
+		default:
+		}
+		return nil
+	})
+	return nil
+}
+
+func (p *plugin) Shutdown(_ *lifecycle.Application) error {
+	return p.remove()
+}
+
+func (p *plugin) remove() error {
+	if err := os.Remove(p.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
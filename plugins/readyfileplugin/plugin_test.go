@@ -0,0 +1,56 @@
+package readyfileplugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+func Test_Plugin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ready")
+	require.NoError(t, os.WriteFile(path, nil, 0o644))
+
+	var stale, ready, shuttingDown bool
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(
+		Plugin(path),
+		&lifecycle.PluginFuncs{
+			StartFunc: func(app *lifecycle.Application) error {
+				stale = exists(path)
+
+				go func() {
+					<-app.Ready()
+					defer app.Shutdown(nil)
+
+					// readiness is signaled asynchronously
+					for i := 0; i < 100 && !ready; i++ {
+						time.Sleep(10 * time.Millisecond)
+						ready = exists(path)
+					}
+				}()
+				return nil
+			},
+			ShutdownFunc: func(app *lifecycle.Application) error {
+				shuttingDown = exists(path)
+				return nil
+			},
+		},
+	)
+
+	require.NoError(t, app.StartE())
+	require.False(t, stale, "stale file not removed")
+	require.True(t, ready, "file not created once ready")
+	require.False(t, shuttingDown, "file not removed once shutdown began")
+	require.False(t, exists(path))
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,3 @@
+// Package sentryplugin provides a plugin that reports lifecycle errors and plugin panics to Sentry, flushing pending
+// events when the application is shutdown so they are not dropped as the process exits.
+package sentryplugin
@@ -0,0 +1,127 @@
+package sentryplugin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// DefaultFlushTimeout is how long the plugin waits for pending events to be delivered when the application is
+// shutdown.
+const DefaultFlushTimeout = 2 * time.Second
+
+// Plugin returns a plugin that initializes a Sentry client using the provided options and attaches its hub to the
+// application context (see sentry.GetHubFromContext). Errors returned by plugins, and panics raised by them, are
+// reported to Sentry along with the plugin and phase they occurred in. Pending events are flushed and the client is
+// closed when the application is shutdown (or restarted). The plugin should be registered first so that errors
+// encountered by the plugins following it are reported.
+func Plugin(options sentry.ClientOptions) lifecycle.Plugin {
+	return &plugin{
+		options:      options,
+		flushTimeout: DefaultFlushTimeout,
+	}
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	options      sentry.ClientOptions
+	flushTimeout time.Duration
+
+	mu     sync.Mutex
+	hub    *sentry.Hub
+	client *sentry.Client
+}
+
+func (p *plugin) Name() string {
+	return "sentry"
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	client, err := sentry.NewClient(p.options)
+	if err != nil {
+		return err
+	}
+
+	hub := sentry.NewHub(client, sentry.NewScope())
+	app.WithValue(sentry.HubContextKey, hub)
+
+	p.mu.Lock()
+	p.hub = hub
+	p.client = client
+	p.mu.Unlock()
+
+	app.AddHook(p.report)
+	app.AddInterceptor(p.recover)
+	return nil
+}
+
+func (p *plugin) Shutdown(_ *lifecycle.Application) error {
+	// the hub remains attached to the application, so events reported once the client is closed are dropped
+	p.mu.Lock()
+	client := p.client
+	p.client = nil
+	p.mu.Unlock()
+
+	if client == nil {
+		return nil
+	}
+	defer client.Close()
+
+	if !client.Flush(p.flushTimeout) {
+		return fmt.Errorf("timed out flushing events after %s", p.flushTimeout)
+	}
+	return nil
+}
+
+// currentHub returns the hub created when the plugin was last initialized.
+func (p *plugin) currentHub() *sentry.Hub {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.hub
+}
+
+// report captures the errors returned by plugins, and those encountered completing a phase (such as timeouts).
+func (p *plugin) report(event lifecycle.Event) {
+	if event.Err == nil || (event.Kind != lifecycle.EventPlugin && event.Kind != lifecycle.EventPhaseExit) {
+		return
+	}
+
+	hub := p.currentHub()
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTag("lifecycle.phase", string(event.Phase))
+		if event.Plugin != "" {
+			scope.SetTag("lifecycle.plugin", event.Plugin)
+		}
+
+		hub.CaptureException(event.Err)
+	})
+}
+
+// recover reports panics raised by plugins before propagating them. Pending events are flushed since the panic may
+// terminate the process.
+func (p *plugin) recover(plugin string, phase lifecycle.Phase, next lifecycle.Invoker) lifecycle.Invoker {
+	return func(ctx context.Context, app *lifecycle.Application) error {
+		defer func() {
+			if r := recover(); r != nil {
+				hub := p.currentHub()
+				hub.WithScope(func(scope *sentry.Scope) {
+					scope.SetTag("lifecycle.phase", string(phase))
+					scope.SetTag("lifecycle.plugin", plugin)
+
+					hub.Recover(r)
+				})
+
+				hub.Flush(p.flushTimeout)
+				panic(r)
+			}
+		}()
+
+		return next(ctx, app)
+	}
+}
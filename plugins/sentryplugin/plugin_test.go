@@ -0,0 +1,136 @@
+package sentryplugin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+type transport struct {
+	mu      sync.Mutex
+	events  []*sentry.Event
+	flushed int
+	closed  int
+}
+
+func (t *transport) Configure(_ sentry.ClientOptions) {}
+
+func (t *transport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.events = append(t.events, event)
+}
+
+func (t *transport) Flush(_ time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.flushed++
+	return true
+}
+
+func (t *transport) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closed++
+}
+
+func Test_Plugin(t *testing.T) {
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+
+	transport := &transport{}
+	app.Initialize(
+		Plugin(sentry.ClientOptions{Transport: transport}),
+		&lifecycle.PluginFuncs{
+			RunFunc: func(app *lifecycle.Application) error {
+				require.NotNil(t, sentry.GetHubFromContext(app.Context()), "hub not attached")
+				return fmt.Errorf("something went wrong")
+			},
+		},
+	)
+
+	require.Error(t, app.RunE())
+
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+
+	require.Len(t, transport.events, 1)
+	require.Equal(t, "run", transport.events[0].Tags["lifecycle.phase"])
+	require.Equal(t, "plugin[1] (*lifecycle.PluginFuncs)", transport.events[0].Tags["lifecycle.plugin"])
+	require.Equal(t, 1, transport.flushed)
+	require.Equal(t, 1, transport.closed)
+}
+
+func Test_Plugin_Panic(t *testing.T) {
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+
+	transport := &transport{}
+	app.Initialize(
+		Plugin(sentry.ClientOptions{Transport: transport}),
+		&lifecycle.PluginFuncs{
+			StartFunc: func(app *lifecycle.Application) error {
+				panic("nil map")
+			},
+		},
+	)
+
+	require.PanicsWithValue(t, "nil map", func() {
+		_ = app.StartE()
+	})
+
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+
+	require.Len(t, transport.events, 1)
+	require.Equal(t, "nil map", transport.events[0].Message)
+	require.Equal(t, "start", transport.events[0].Tags["lifecycle.phase"])
+	require.Equal(t, 1, transport.flushed)
+}
+
+func Test_Plugin_Restart(t *testing.T) {
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+
+	transport := &transport{}
+	starts := 0
+	app.Initialize(
+		Plugin(sentry.ClientOptions{Transport: transport}),
+		&lifecycle.PluginFuncs{
+			StartFunc: func(app *lifecycle.Application) error {
+				starts++
+				if starts > 1 {
+					return fmt.Errorf("something went wrong")
+				}
+				return nil
+			},
+		},
+	)
+
+	app.AfterStart(func(_ context.Context) error {
+		go func() {
+			_ = app.Restart()
+		}()
+		return nil
+	})
+
+	require.Error(t, app.StartE())
+
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+
+	// errors are captured once, rather than once for each time the plugin was initialized
+	require.Len(t, transport.events, 1)
+	require.Equal(t, "start", transport.events[0].Tags["lifecycle.phase"])
+
+	// the client created before restarting is flushed and closed along with the one replacing it
+	require.Equal(t, 2, transport.flushed)
+	require.Equal(t, 2, transport.closed)
+}
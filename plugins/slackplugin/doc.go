@@ -0,0 +1,2 @@
+// Package slackplugin provides a hook posting lifecycle failures to a Slack incoming webhook.
+package slackplugin
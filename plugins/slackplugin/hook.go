@@ -0,0 +1,197 @@
+package slackplugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+const (
+	// DefaultInterval is the minimum amount of time between messages when no interval is configured, matching the rate
+	// limit Slack applies to incoming webhooks.
+	DefaultInterval = time.Second
+	// DefaultTimeout bounds each attempt to post a message when no client is configured.
+	DefaultTimeout = 5 * time.Second
+	// DefaultFlushTimeout bounds how long pending messages are posted for once the application has terminated, when no
+	// flush timeout is configured.
+	DefaultFlushTimeout = 10 * time.Second
+	// queueSize is the number of messages that can be pending before further failures are dropped.
+	queueSize = 16
+)
+
+// Option configures how messages are posted.
+type Option func(n *notifier)
+
+// WithClient configures the client used to post messages.
+func WithClient(client *http.Client) Option {
+	return func(n *notifier) {
+		n.client = client
+	}
+}
+
+// WithInterval configures the minimum amount of time between messages.
+func WithInterval(interval time.Duration) Option {
+	return func(n *notifier) {
+		n.interval = interval
+	}
+}
+
+// WithFlushTimeout configures how long pending messages are posted for once the application has terminated. Messages
+// still pending when it elapses are dropped.
+func WithFlushTimeout(timeout time.Duration) Option {
+	return func(n *notifier) {
+		n.flushTimeout = timeout
+	}
+}
+
+// Hook returns a hook posting a message to the provided Slack incoming webhook URL each time a plugin fails, or a
+// phase fails to complete (such as when timing out). Messages describe the plugin, phase, and error. Messages are
+// posted asynchronously, no more than once per interval (see WithInterval), so the hook never blocks the lifecycle.
+// Failures occurring while too many messages are pending are dropped, and the number dropped is included in the next
+// message posted. Once the application has terminated, pending messages are posted before the process exits (see
+// WithFlushTimeout).
+func Hook(url string, opts ...Option) lifecycle.Hook {
+	n := &notifier{
+		url:          url,
+		client:       &http.Client{Timeout: DefaultTimeout},
+		interval:     DefaultInterval,
+		flushTimeout: DefaultFlushTimeout,
+		queue:        make(chan post, queueSize),
+	}
+
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n.report
+}
+
+type notifier struct {
+	url          string
+	client       *http.Client
+	interval     time.Duration
+	flushTimeout time.Duration
+
+	once  sync.Once
+	queue chan post
+
+	mu      sync.Mutex
+	dropped int
+}
+
+// post is a message pending in the queue, or a request to be notified once the messages ahead of it have been posted.
+type post struct {
+	text    string
+	flushed chan struct{}
+}
+
+func (n *notifier) report(event lifecycle.Event) {
+	if event.Kind == lifecycle.EventPhaseEnter && event.Phase == lifecycle.PhaseTerminated {
+		n.flush()
+		return
+	}
+
+	if event.Err == nil || (event.Kind != lifecycle.EventPlugin && event.Kind != lifecycle.EventPhaseExit) {
+		return
+	}
+
+	n.once.Do(func() {
+		go n.post()
+	})
+
+	select {
+	case n.queue <- post{text: message(event)}:
+	default:
+		n.mu.Lock()
+		n.dropped++
+		n.mu.Unlock()
+	}
+}
+
+// message formats the event as a Slack message.
+func message(event lifecycle.Event) string {
+	err := event.Err
+
+	// the plugin and phase are described separately
+	var pluginErr *lifecycle.PluginError
+	if errors.As(err, &pluginErr) && pluginErr.Plugin == event.Plugin {
+		err = pluginErr.Err
+	}
+
+	if event.Plugin == "" {
+		return fmt.Sprintf(":rotating_light: *%s failed*: %s", event.Phase, err)
+	}
+	return fmt.Sprintf(":rotating_light: *%s failed to %s*: %s", event.Plugin, event.Phase, err)
+}
+
+// flush waits for the pending messages to be posted, for no longer than the flush timeout.
+func (n *notifier) flush() {
+	n.once.Do(func() {
+		go n.post()
+	})
+
+	timer := time.NewTimer(n.flushTimeout)
+	defer timer.Stop()
+
+	flushed := make(chan struct{})
+	select {
+	case n.queue <- post{flushed: flushed}:
+	case <-timer.C:
+		return
+	}
+
+	select {
+	case <-flushed:
+	case <-timer.C:
+	}
+}
+
+// post delivers pending messages, waiting at least the configured interval between each.
+func (n *notifier) post() {
+	var last time.Time
+
+	for p := range n.queue {
+		if p.flushed != nil {
+			close(p.flushed)
+			continue
+		}
+
+		text := p.text
+
+		n.mu.Lock()
+		if n.dropped > 0 {
+			text = fmt.Sprintf("%s\n_%d more failures were dropped_", text, n.dropped)
+			n.dropped = 0
+		}
+		n.mu.Unlock()
+
+		time.Sleep(time.Until(last.Add(n.interval)))
+
+		// failures are not reported since doing so could produce further failures
+		_ = n.send(text)
+		last = time.Now()
+	}
+}
+
+func (n *notifier) send(text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package slackplugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+func Test_Hook(t *testing.T) {
+	messages := make(chan string, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		messages <- payload["text"]
+	}))
+	defer server.Close()
+
+	app := lifecycle.NewApplication(
+		lifecycle.WithTerminator(func(err error) {}),
+		lifecycle.WithHook(Hook(server.URL, WithInterval(time.Millisecond))),
+	)
+
+	app.Initialize(&lifecycle.PluginFuncs{
+		RunFunc: func(app *lifecycle.Application) error {
+			return fmt.Errorf("connection refused")
+		},
+	})
+
+	require.Error(t, app.RunE())
+
+	// pending messages are posted before the application terminates
+	select {
+	case text := <-messages:
+		require.Equal(t, ":rotating_light: *plugin[0] (*lifecycle.PluginFuncs) failed to run*: connection refused", text)
+	default:
+		t.Fatal("message not posted")
+	}
+}
+
+func Test_Hook_FlushTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	app := lifecycle.NewApplication(
+		lifecycle.WithTerminator(func(err error) {}),
+		lifecycle.WithHook(Hook(server.URL, WithFlushTimeout(50*time.Millisecond))),
+	)
+
+	app.Initialize(&lifecycle.PluginFuncs{
+		RunFunc: func(app *lifecycle.Application) error {
+			return fmt.Errorf("connection refused")
+		},
+	})
+
+	started := time.Now()
+	require.Error(t, app.RunE())
+	require.Less(t, time.Since(started), DefaultTimeout, "termination not bounded by the flush timeout")
+}
+
+func Test_Hook_Dropped(t *testing.T) {
+	n := &notifier{queue: make(chan post, queueSize)}
+	n.once.Do(func() {}) // prevent messages from being posted
+
+	for i := 0; i < queueSize+2; i++ {
+		n.report(lifecycle.Event{Kind: lifecycle.EventPlugin, Phase: lifecycle.PhaseStart, Err: fmt.Errorf("failed")})
+	}
+
+	require.Len(t, n.queue, queueSize)
+	require.Equal(t, 2, n.dropped)
+}
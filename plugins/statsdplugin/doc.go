@@ -0,0 +1,3 @@
+// Package statsdplugin provides a plugin managing a StatsD (or Datadog agent) client, emitting the duration of each
+// phase of the lifecycle as timing metrics.
+package statsdplugin
@@ -0,0 +1,19 @@
+module github.com/effxhq/go-lifecycle/plugins/statsdplugin
+
+go 1.21
+
+require (
+	github.com/DataDog/datadog-go/v5 v5.5.0
+	github.com/effxhq/go-lifecycle v0.2.0
+	github.com/stretchr/testify v1.8.4
+)
+
+require (
+	github.com/Microsoft/go-winio v0.5.0 // indirect
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	golang.org/x/sys v0.17.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/effxhq/go-lifecycle => ../..
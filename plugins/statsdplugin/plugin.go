@@ -0,0 +1,119 @@
+package statsdplugin
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+const (
+	// PhaseMetric is the timing metric reporting how long the application took to complete each phase.
+	PhaseMetric = "lifecycle.phase.duration"
+	// PluginMetric is the timing metric reporting how long each plugin took to complete each phase.
+	PluginMetric = "lifecycle.plugin.duration"
+)
+
+type contextKey struct{}
+
+func (contextKey) String() string {
+	return "statsd client"
+}
+
+// Plugin returns a plugin that connects a StatsD client to the provided address during initialization and attaches it
+// to the application context (see FromContext). The duration of each phase, and of each plugin within it, is reported
+// as a timing metric tagged with the phase (and the plugin and its status). Buffered metrics are flushed and the
+// client closed when the application is shutdown. The plugin should be registered first so that the plugins following
+// it are measured.
+func Plugin(addr string, opts ...statsd.Option) lifecycle.Plugin {
+	return &plugin{
+		addr: addr,
+		opts: opts,
+	}
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	addr string
+	opts []statsd.Option
+
+	mu     sync.Mutex
+	client *statsd.Client
+}
+
+func (p *plugin) Name() string {
+	return "statsd"
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	client, err := statsd.New(p.addr, p.opts...)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.client = client
+	p.mu.Unlock()
+
+	app.WithValue(contextKey{}, client)
+	app.AddHook(p.report)
+	return nil
+}
+
+func (p *plugin) Shutdown(_ *lifecycle.Application) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client == nil {
+		return nil
+	}
+
+	client := p.client
+	p.client = nil
+
+	return errors.Join(client.Flush(), client.Close())
+}
+
+// report emits the duration of completed phases and plugins. Events delivered once the client is closed are dropped.
+func (p *plugin) report(event lifecycle.Event) {
+	var (
+		name string
+		tags = []string{"phase:" + string(event.Phase)}
+	)
+
+	switch event.Kind {
+	case lifecycle.EventPhaseExit:
+		name = PhaseMetric
+	case lifecycle.EventPlugin:
+		name = PluginMetric
+		tags = append(tags, "plugin:"+event.Plugin, "status:"+status(event.Err))
+	default:
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil {
+		_ = p.client.Timing(name, event.Duration, tags, 1)
+	}
+}
+
+func status(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// FromContext returns the StatsD client attached to the provided context.
+func FromContext(ctx context.Context) (statsd.ClientInterface, bool) {
+	client, ok := ctx.Value(contextKey{}).(*statsd.Client)
+	if !ok {
+		return nil, false
+	}
+	return client, true
+}
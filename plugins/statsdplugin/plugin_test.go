@@ -0,0 +1,80 @@
+package statsdplugin
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// receive returns the metrics received until the connection is idle.
+func receive(t *testing.T, conn net.PacketConn) string {
+	var received strings.Builder
+	buf := make([]byte, 65536)
+	for {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+		received.Write(buf[:n])
+	}
+	return received.String()
+}
+
+func Test_Plugin(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(
+		Plugin(conn.LocalAddr().String(), statsd.WithoutTelemetry()),
+		&lifecycle.PluginFuncs{
+			RunFunc: func(app *lifecycle.Application) error {
+				_, ok := FromContext(app.Context())
+				require.True(t, ok, "client not attached")
+				return nil
+			},
+		},
+	)
+
+	require.NoError(t, app.RunE())
+
+	metrics := receive(t, conn)
+	require.Contains(t, metrics, PhaseMetric+":")
+	require.Contains(t, metrics, "|#phase:initialize")
+	require.Contains(t, metrics, PluginMetric+":")
+	require.Contains(t, metrics, "|#phase:run,plugin:plugin[1] (*lifecycle.PluginFuncs),status:ok")
+}
+
+func Test_Plugin_Restart(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(Plugin(conn.LocalAddr().String(), statsd.WithoutTelemetry()))
+
+	restarted := make(chan error, 1)
+	app.AfterStart(func(_ context.Context) error {
+		go func() {
+			restarted <- app.Restart()
+			app.Shutdown(nil)
+		}()
+		return nil
+	})
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, <-restarted)
+
+	// metrics are emitted once, rather than once for each time the plugin was initialized
+	metrics := receive(t, conn)
+	require.Equal(t, 1, strings.Count(metrics, "|#phase:restart"))
+}
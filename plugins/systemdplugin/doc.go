@@ -0,0 +1,3 @@
+// Package systemdplugin provides a plugin integrating the lifecycle of the application with systemd, so that units of
+// Type=notify work out of the box.
+package systemdplugin
@@ -0,0 +1,68 @@
+package systemdplugin
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// SocketEnv is the environment variable systemd provides the address of the notification socket in.
+	SocketEnv = "NOTIFY_SOCKET"
+	// WatchdogEnv is the environment variable systemd provides the watchdog timeout in, in microseconds.
+	WatchdogEnv = "WATCHDOG_USEC"
+	// WatchdogPIDEnv is the environment variable systemd provides the process expected to send keep-alive pings in.
+	WatchdogPIDEnv = "WATCHDOG_PID"
+)
+
+// Notification states understood by systemd (see sd_notify(3)).
+const (
+	Ready    = "READY=1"
+	Stopping = "STOPPING=1"
+	Watchdog = "WATCHDOG=1"
+)
+
+// Notify sends the provided state to systemd using the socket named by SocketEnv. False is returned when the process
+// was not started by systemd with notifications enabled.
+func Notify(state string) (bool, error) {
+	addr := os.Getenv(SocketEnv)
+	if addr == "" {
+		return false, nil
+	}
+
+	// abstract sockets are denoted by a leading '@'
+	if addr[0] == '@' {
+		addr = "\x00" + addr[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Status returns the state describing the status of the service.
+func Status(status string) string {
+	return "STATUS=" + status
+}
+
+// WatchdogInterval returns how often keep-alive pings should be sent to systemd, which is half of the watchdog timeout
+// configured for the unit. False is returned when the watchdog is not enabled for this process.
+func WatchdogInterval() (time.Duration, bool) {
+	if pid := os.Getenv(WatchdogPIDEnv); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, false
+	}
+
+	usec, err := strconv.ParseInt(os.Getenv(WatchdogEnv), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+	return time.Duration(usec) * time.Microsecond / 2, true
+}
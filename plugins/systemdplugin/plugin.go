@@ -0,0 +1,106 @@
+package systemdplugin
+
+import (
+	"context"
+	"time"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// Plugin returns a plugin notifying systemd of the lifecycle of the application. READY=1 is sent once the application
+// has started and is ready (see Application.Ready), STOPPING=1 as soon as shutdown begins, and STATUS= as the
+// application enters each phase. When the watchdog is enabled for the unit (see WatchdogInterval), keep-alive pings are
+// sent once the application has started for as long as its plugins are healthy (see Application.Health), allowing
+// systemd to restart an unhealthy service. When the process was not started by systemd with notifications enabled, the
+// plugin does nothing. The plugin should be registered first so the status reflects the phases of the plugins following
+// it.
+func Plugin() lifecycle.Plugin {
+	return &plugin{}
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	enabled bool
+}
+
+func (p *plugin) Name() string {
+	return "systemd"
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	enabled, err := Notify(Status(string(lifecycle.PhaseInitialize)))
+	if err != nil || !enabled {
+		return err
+	}
+
+	p.enabled = true
+
+	app.AddHook(func(event lifecycle.Event) {
+		if event.Kind == lifecycle.EventPhaseEnter {
+			p.notify(app, Status(string(event.Phase)))
+		}
+	})
+
+	app.OnStateChange(func(_, to lifecycle.State) {
+		if to == lifecycle.StateShutdown {
+			p.notify(app, Stopping)
+		}
+	})
+	return nil
+}
+
+func (p *plugin) Start(app *lifecycle.Application) error {
+	if !p.enabled {
+		return nil
+	}
+
+	app.Go(func(ctx context.Context) error {
+		select {
+		case <-app.Ready():
+			_, err := Notify(Ready)
+			return err
+		case <-ctx.Done():
+			return nil
+		}
+	})
+
+	if interval, ok := WatchdogInterval(); ok {
+		app.Go(func(ctx context.Context) error {
+			p.keepAlive(ctx, app, interval)
+			return nil
+		})
+	}
+	return nil
+}
+
+// keepAlive pings the systemd watchdog on the provided interval until the context is canceled. Pings are skipped while
+// any plugin is unhealthy.
+func (p *plugin) keepAlive(ctx context.Context, app *lifecycle.Application, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, interval)
+		err := app.Health(checkCtx).Err()
+		cancel()
+
+		if err != nil {
+			app.Logger().Warn("skipped systemd watchdog ping", "error", err)
+		} else {
+			p.notify(app, Watchdog)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// notify sends the provided state, logging failures since they should not interrupt the lifecycle.
+func (p *plugin) notify(app *lifecycle.Application, state string) {
+	if _, err := Notify(state); err != nil {
+		app.Logger().Warn("failed to notify systemd", "state", state, "error", err)
+	}
+}
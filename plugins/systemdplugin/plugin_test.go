@@ -0,0 +1,178 @@
+//go:build !windows
+
+package systemdplugin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// listen returns a socket receiving notifications, as systemd would.
+func listen(t *testing.T) *net.UnixConn {
+	addr := &net.UnixAddr{Name: filepath.Join(t.TempDir(), "notify.sock"), Net: "unixgram"}
+
+	conn, err := net.ListenUnixgram("unixgram", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	t.Setenv(SocketEnv, addr.Name)
+	return conn
+}
+
+// received returns the notifications received until none arrive for a short period.
+func received(conn *net.UnixConn) []string {
+	states := make([]string, 0)
+	buf := make([]byte, 4096)
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond)); err != nil {
+			return states
+		}
+
+		n, err := conn.Read(buf)
+		if err != nil {
+			return states
+		}
+		states = append(states, string(buf[:n]))
+	}
+}
+
+// collect receives notifications in the background, so the socket does not fill up when many are sent, until the
+// returned function is called.
+func collect(conn *net.UnixConn) func() []string {
+	done := make(chan struct{})
+	collected := make(chan []string, 1)
+
+	go func() {
+		states := make([]string, 0)
+		for {
+			states = append(states, received(conn)...)
+
+			select {
+			case <-done:
+				collected <- append(states, received(conn)...)
+				return
+			default:
+			}
+		}
+	}()
+
+	return func() []string {
+		close(done)
+		return <-collected
+	}
+}
+
+func Test_Notify_Disabled(t *testing.T) {
+	t.Setenv(SocketEnv, "")
+
+	enabled, err := Notify(Ready)
+	require.NoError(t, err)
+	require.False(t, enabled)
+}
+
+func Test_Plugin(t *testing.T) {
+	conn := listen(t)
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(Plugin())
+
+	app.AfterStart(func(ctx context.Context) error {
+		go func() {
+			<-app.Ready()
+			// allow the plugin to notify systemd of readiness before shutting down
+			time.Sleep(50 * time.Millisecond)
+			app.Shutdown(nil)
+		}()
+		return nil
+	})
+
+	require.NoError(t, app.StartE())
+	require.Equal(t, []string{
+		"STATUS=initialize",
+		"STATUS=start",
+		"READY=1",
+		"STOPPING=1",
+		"STATUS=shutdown",
+		"STATUS=terminated",
+	}, received(conn))
+}
+
+func Test_Plugin_Restart(t *testing.T) {
+	conn := listen(t)
+
+	stop := collect(conn)
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(Plugin())
+
+	restarted := make(chan error, 1)
+	app.AfterStart(func(ctx context.Context) error {
+		go func() {
+			<-app.Ready()
+			restarted <- app.Restart()
+			app.Shutdown(nil)
+		}()
+		return nil
+	})
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, <-restarted)
+
+	// each notification is sent once, rather than once for each time the plugin was initialized
+	counts := make(map[string]int)
+	for _, state := range stop() {
+		counts[state]++
+	}
+	require.Equal(t, 1, counts["STATUS=restart"])
+	require.Equal(t, 1, counts[Stopping])
+}
+
+type healthPlugin struct {
+	lifecycle.PluginFuncs
+	healthy atomic.Bool
+}
+
+func (p *healthPlugin) Healthy(_ context.Context) error {
+	if !p.healthy.Load() {
+		return fmt.Errorf("connection refused")
+	}
+	return nil
+}
+
+func Test_Plugin_Watchdog(t *testing.T) {
+	conn := listen(t)
+	t.Setenv(WatchdogEnv, "20000")
+
+	health := &healthPlugin{}
+	health.healthy.Store(true)
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(Plugin(), health)
+
+	var healthy, unhealthy []string
+	go func() {
+		<-app.Ready()
+		defer app.Shutdown(nil)
+
+		// pings are sent every 10ms while healthy
+		time.Sleep(50 * time.Millisecond)
+		health.healthy.Store(false)
+		healthy = received(conn)
+
+		time.Sleep(50 * time.Millisecond)
+		unhealthy = received(conn)
+	}()
+
+	require.NoError(t, app.StartE())
+	require.Contains(t, healthy, Watchdog)
+	require.NotContains(t, unhealthy, Watchdog)
+}
@@ -0,0 +1,3 @@
+// Package tlsplugin provides a plugin managing a tls.Config whose certificate is reloaded as the certificate and key
+// files change, allowing certificates to be rotated without restarting the application.
+package tlsplugin
@@ -0,0 +1,171 @@
+package tlsplugin
+
+import (
+	"context"
+	"crypto/tls"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// Name is the name of the plugin, which server plugins can depend on.
+const Name = "tls"
+
+type contextKey struct{}
+
+func (contextKey) String() string {
+	return "tls config"
+}
+
+// Option configures the plugin.
+type Option func(p *plugin)
+
+// WithConfig configures the settings (such as the minimum version or client authentication) of the provided tls.Config.
+// The certificate is always managed by the plugin.
+func WithConfig(config *tls.Config) Option {
+	return func(p *plugin) {
+		p.base = config
+	}
+}
+
+// Plugin returns a plugin loading the certificate and key from the provided files during initialization and attaching
+// a tls.Config serving the certificate to the application context (see FromContext). The certificate is reloaded as
+// the files change (including when replaced, as is done when Kubernetes updates a mounted secret) and when the
+// application is reloaded (such as on SIGHUP when configured using lifecycle.WithReloadSignals). Should reloading
+// fail, the previous certificate continues to be served. The file watchers are closed when the application is
+// shutdown.
+func Plugin(certFile, keyFile string, opts ...Option) lifecycle.Plugin {
+	p := &plugin{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	certFile string
+	keyFile  string
+	base     *tls.Config
+
+	certificate atomic.Pointer[tls.Certificate]
+	watcher     *fsnotify.Watcher
+	watching    chan struct{}
+}
+
+func (p *plugin) Name() string {
+	return Name
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	if err := p.load(); err != nil {
+		return err
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if p.base != nil {
+		config = p.base.Clone()
+	}
+
+	config.Certificates = nil
+	config.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return p.certificate.Load(), nil
+	}
+
+	Provide(app, config)
+	return p.watch(app)
+}
+
+func (p *plugin) Reload(_ *lifecycle.Application) error {
+	return p.load()
+}
+
+func (p *plugin) Shutdown(_ *lifecycle.Application) error {
+	if p.watcher == nil {
+		return nil
+	}
+
+	err := p.watcher.Close()
+	<-p.watching
+	return err
+}
+
+var _ lifecycle.Reloader = &plugin{}
+
+// load reads the certificate and key, replacing the certificate being served.
+func (p *plugin) load() error {
+	certificate, err := tls.LoadX509KeyPair(p.certFile, p.keyFile)
+	if err != nil {
+		return err
+	}
+
+	p.certificate.Store(&certificate)
+	return nil
+}
+
+// watch reloads the certificate as the files change. The directories containing the files are watched since files
+// replaced by a rename (or a symlink swap) are no longer watched once replaced.
+func (p *plugin) watch(app *lifecycle.Application) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range []string{filepath.Dir(p.certFile), filepath.Dir(p.keyFile)} {
+		if err := watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return err
+		}
+	}
+
+	p.watcher = watcher
+	p.watching = make(chan struct{})
+
+	go func() {
+		defer close(p.watching)
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if event.Has(fsnotify.Remove) || event.Has(fsnotify.Chmod) {
+					continue
+				}
+
+				// the files may be partially written, in which case they're reloaded once the write completes
+				if err := p.load(); err != nil {
+					app.Logger().Debug("failed to reload certificate", "file", event.Name, "error", err)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				app.Logger().Warn("failed to watch certificate", "error", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Provide attaches the provided tls.Config to the application context, allowing plugins managing certificates by other
+// means (such as the autocertplugin package) to share their config with the server plugins. Plugins providing a config
+// should be named Name so server plugins depending on the config are initialized after them.
+func Provide(app *lifecycle.Application, config *tls.Config) {
+	app.WithValue(contextKey{}, config)
+}
+
+// FromContext returns the tls.Config attached to the provided context.
+func FromContext(ctx context.Context) (*tls.Config, bool) {
+	config, ok := ctx.Value(contextKey{}).(*tls.Config)
+	return config, ok
+}
@@ -0,0 +1,97 @@
+package tlsplugin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// writeCertificate writes a self-signed certificate for the provided common name to the provided files.
+func writeCertificate(t *testing.T, certFile, keyFile, name string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	encodedKey, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: encodedKey})
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+}
+
+// served returns the common name of the certificate served using the provided config.
+func served(t *testing.T, config *tls.Config) string {
+	certificate, err := config.GetCertificate(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	require.NoError(t, err)
+	return leaf.Subject.CommonName
+}
+
+func Test_Plugin(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeCertificate(t, certFile, keyFile, "first")
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(
+		Plugin(certFile, keyFile, WithConfig(&tls.Config{MinVersion: tls.VersionTLS13})),
+		&lifecycle.PluginFuncs{
+			RunFunc: func(app *lifecycle.Application) error {
+				config, ok := FromContext(app.Context())
+				require.True(t, ok, "config not attached")
+				require.Equal(t, uint16(tls.VersionTLS13), config.MinVersion)
+				require.Equal(t, "first", served(t, config))
+
+				// the certificate is reloaded as the files change
+				writeCertificate(t, certFile, keyFile, "second")
+				require.Eventually(t, func() bool {
+					return served(t, config) == "second"
+				}, 5*time.Second, 10*time.Millisecond)
+
+				// and when the application is reloaded
+				writeCertificate(t, certFile, keyFile, "third")
+				require.NoError(t, app.Reload())
+				require.Equal(t, "third", served(t, config))
+				return nil
+			},
+		},
+	)
+
+	require.NoError(t, app.RunE())
+}
+
+func Test_Plugin_MissingFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(Plugin(filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")))
+
+	require.ErrorIs(t, app.RunE(), os.ErrNotExist)
+}
@@ -0,0 +1,3 @@
+// Package webhookplugin provides a plugin posting the lifecycle transitions of the application to webhooks, so that
+// external systems (such as deploy dashboards) learn when the application starts, fails, and terminates.
+package webhookplugin
@@ -0,0 +1,337 @@
+package webhookplugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+// Events posted to the webhooks.
+const (
+	// EventStarted is posted once the application has started and is ready.
+	EventStarted = "started"
+	// EventShuttingDown is posted as soon as the application begins shutting down.
+	EventShuttingDown = "shutting_down"
+	// EventTerminated is posted once the application has terminated.
+	EventTerminated = "terminated"
+	// EventError is posted when a plugin returns an error, or a phase fails to complete (such as when timing out).
+	EventError = "error"
+)
+
+const (
+	// DefaultRetries is how many times delivering a notification is retried when no retries are configured.
+	DefaultRetries = 3
+	// DefaultBackoff is how long the plugin waits before first retrying a delivery when no backoff is configured. The
+	// backoff doubles with each retry.
+	DefaultBackoff = 500 * time.Millisecond
+	// DefaultTimeout bounds each attempt to deliver a notification when no client is configured.
+	DefaultTimeout = 5 * time.Second
+	// DefaultFlushTimeout bounds how long pending notifications are delivered for once the application has terminated,
+	// when no flush timeout is configured.
+	DefaultFlushTimeout = 10 * time.Second
+	// queueSize is the number of notifications that can be pending before further notifications are dropped.
+	queueSize = 64
+)
+
+// Notification is the JSON payload posted to the webhooks.
+type Notification struct {
+	Event  string    `json:"event"`
+	Time   time.Time `json:"time"`
+	State  string    `json:"state"`
+	Phase  string    `json:"phase,omitempty"`
+	Plugin string    `json:"plugin,omitempty"`
+	Error  string    `json:"error,omitempty"`
+	Reason string    `json:"reason,omitempty"`
+	// Dropped is the number of notifications dropped since the previous notification was delivered, which happens when
+	// too many notifications are pending (such as while the webhook is unreachable).
+	Dropped int `json:"dropped,omitempty"`
+}
+
+// Option configures the delivery of notifications.
+type Option func(p *plugin)
+
+// WithClient configures the client used to post notifications.
+func WithClient(client *http.Client) Option {
+	return func(p *plugin) {
+		p.client = client
+	}
+}
+
+// WithRetries configures how many times delivering a notification is retried. Zero disables retries.
+func WithRetries(retries int) Option {
+	return func(p *plugin) {
+		p.retries = retries
+	}
+}
+
+// WithBackoff configures how long the plugin waits before first retrying a delivery. The backoff doubles with each
+// retry.
+func WithBackoff(backoff time.Duration) Option {
+	return func(p *plugin) {
+		p.backoff = backoff
+	}
+}
+
+// WithFlushTimeout configures how long pending notifications are delivered for once the application has terminated.
+// Notifications still pending when it elapses are dropped.
+func WithFlushTimeout(timeout time.Duration) Option {
+	return func(p *plugin) {
+		p.flushTimeout = timeout
+	}
+}
+
+// Plugin returns a plugin posting notifications (see Notification) to each of the provided URLs as the application
+// transitions through its lifecycle. Notifications are delivered in the order they occurred by a background go-routine
+// so the lifecycle is not blocked, and retried when the webhook cannot be reached or responds with an unsuccessful
+// status. Notifications occurring while too many are pending are dropped, and the number dropped is included in the
+// next notification delivered. Once the application has terminated, pending notifications are delivered before the
+// process exits (see WithFlushTimeout). The plugin should be registered first so that errors encountered by the plugins
+// following it are posted.
+func Plugin(urls []string, opts ...Option) lifecycle.Plugin {
+	p := &plugin{
+		urls:         urls,
+		client:       &http.Client{Timeout: DefaultTimeout},
+		retries:      DefaultRetries,
+		backoff:      DefaultBackoff,
+		flushTimeout: DefaultFlushTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	urls         []string
+	client       *http.Client
+	retries      int
+	backoff      time.Duration
+	flushTimeout time.Duration
+
+	mu        sync.Mutex
+	closed    bool
+	started   sync.Once
+	queue     chan Notification
+	delivered chan struct{}
+	abandoned chan struct{}
+	dropped   int32
+}
+
+func (p *plugin) Name() string {
+	return "webhook"
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	// notifications are delivered in order by a single go-routine, which continues across restarts of the application
+	p.mu.Lock()
+	if p.queue == nil {
+		p.queue = make(chan Notification, queueSize)
+		p.delivered = make(chan struct{})
+		p.abandoned = make(chan struct{})
+
+		go p.deliver(app)
+	}
+	p.mu.Unlock()
+
+	app.AddHook(func(event lifecycle.Event) {
+		p.report(app, event)
+	})
+
+	app.OnStateChange(func(_, to lifecycle.State) {
+		if to == lifecycle.StateShutdown {
+			// the started event may not have been posted yet when shutdown immediately follows readiness
+			select {
+			case <-app.Ready():
+				p.notifyStarted(app)
+			default:
+			}
+
+			p.enqueue(notification(app, EventShuttingDown, func(n *Notification) {
+				n.Reason = app.ShutdownReason().String()
+			}))
+		}
+	})
+	return nil
+}
+
+func (p *plugin) Start(app *lifecycle.Application) error {
+	app.Go(func(ctx context.Context) error {
+		select {
+		case <-app.Ready():
+			p.notifyStarted(app)
+		case <-ctx.Done():
+		}
+		return nil
+	})
+	return nil
+}
+
+// notifyStarted posts the started event once (rather than each time the application restarts), keeping it ordered
+// before the shutting down event.
+func (p *plugin) notifyStarted(app *lifecycle.Application) {
+	p.started.Do(func() {
+		p.enqueue(notification(app, EventStarted, nil))
+	})
+}
+
+// report posts errors encountered by plugins and phases. Once the application has terminated, pending notifications
+// are delivered before returning.
+func (p *plugin) report(app *lifecycle.Application, event lifecycle.Event) {
+	switch {
+	case event.Kind == lifecycle.EventPhaseEnter && event.Phase == lifecycle.PhaseTerminated:
+		p.close(app, notification(app, EventTerminated, func(n *Notification) {
+			n.Reason = app.ShutdownReason().String()
+			if event.Err != nil {
+				n.Error = event.Err.Error()
+			}
+		}))
+
+	case event.Err != nil && (event.Kind == lifecycle.EventPlugin || event.Kind == lifecycle.EventPhaseExit):
+		err := event.Err
+
+		// the plugin and phase are reported separately
+		var pluginErr *lifecycle.PluginError
+		if errors.As(err, &pluginErr) && pluginErr.Plugin == event.Plugin {
+			err = pluginErr.Err
+		}
+
+		p.enqueue(notification(app, EventError, func(n *Notification) {
+			n.Phase = string(event.Phase)
+			n.Plugin = event.Plugin
+			n.Error = err.Error()
+		}))
+	}
+}
+
+func notification(app *lifecycle.Application, event string, configure func(n *Notification)) Notification {
+	n := Notification{
+		Event: event,
+		Time:  time.Now(),
+		State: app.State().String(),
+	}
+
+	if configure != nil {
+		configure(&n)
+	}
+	return n
+}
+
+// enqueue schedules the notification for delivery without blocking. Notifications are dropped (and counted) when too
+// many are pending, and once the plugin has been closed.
+func (p *plugin) enqueue(n Notification) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	select {
+	case p.queue <- n:
+	default:
+		atomic.AddInt32(&p.dropped, 1)
+	}
+}
+
+// close schedules the final notification for delivery, stops accepting notifications, and waits for the pending ones
+// to be delivered. Since the lifecycle has ended, the final notification waits for room in the queue rather than being
+// dropped. Notifications still pending once the flush timeout elapses are dropped.
+func (p *plugin) close(app *lifecycle.Application, final Notification) {
+	p.mu.Lock()
+	closed := p.closed
+	p.closed = true
+	p.mu.Unlock()
+
+	if closed {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.flushTimeout)
+	defer cancel()
+
+	// notifications are no longer enqueued once closed, so the queue is only sent to here
+	select {
+	case p.queue <- final:
+	case <-ctx.Done():
+	}
+	close(p.queue)
+
+	select {
+	case <-p.delivered:
+	case <-ctx.Done():
+		close(p.abandoned)
+		app.Logger().Warn("dropped webhook notifications pending after the flush timeout", "timeout", p.flushTimeout)
+	}
+}
+
+func (p *plugin) deliver(app *lifecycle.Application) {
+	defer close(p.delivered)
+
+	for n := range p.queue {
+		// notifications pending once the flush timeout elapses are dropped
+		select {
+		case <-p.abandoned:
+			continue
+		default:
+		}
+
+		n.Dropped = int(atomic.SwapInt32(&p.dropped, 0))
+
+		body, err := json.Marshal(n)
+		if err != nil {
+			app.Logger().Error("failed to encode webhook notification", "event", n.Event, "error", err)
+			continue
+		}
+
+		for _, url := range p.urls {
+			if err := p.post(url, body); err != nil {
+				app.Logger().Warn("failed to post webhook notification", "event", n.Event, "url", url, "error", err)
+			}
+		}
+	}
+}
+
+// post delivers the body to the provided URL, retrying failed attempts.
+func (p *plugin) post(url string, body []byte) error {
+	backoff := p.backoff
+
+	var err error
+	for attempt := 0; attempt <= p.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-p.abandoned:
+				return err
+			}
+			backoff *= 2
+		}
+
+		if err = p.attempt(url, body); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (p *plugin) attempt(url string, body []byte) error {
+	resp, err := p.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
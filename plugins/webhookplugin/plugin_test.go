@@ -0,0 +1,205 @@
+package webhookplugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+type webhook struct {
+	mu            sync.Mutex
+	failures      int
+	notifications []Notification
+}
+
+func (w *webhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// fail the first attempt to exercise retries
+	if w.failures == 0 {
+		w.failures++
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var n Notification
+	if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.notifications = append(w.notifications, n)
+}
+
+func Test_Plugin(t *testing.T) {
+	hook := &webhook{}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(
+		Plugin([]string{server.URL}, WithBackoff(time.Millisecond)),
+		&lifecycle.PluginFuncs{
+			StartFunc: func(app *lifecycle.Application) error {
+				go func() {
+					<-app.Ready()
+					app.Shutdown(fmt.Errorf("out of memory"))
+				}()
+				return nil
+			},
+		},
+	)
+
+	require.EqualError(t, app.StartE(), "out of memory")
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+
+	events := make([]string, len(hook.notifications))
+	for i, n := range hook.notifications {
+		events[i] = n.Event
+	}
+
+	require.Equal(t, 1, hook.failures)
+	require.Equal(t, []string{EventStarted, EventShuttingDown, EventTerminated}, events)
+	require.Equal(t, "requested: out of memory", hook.notifications[1].Reason)
+	require.Equal(t, "terminated", hook.notifications[2].State)
+	require.Equal(t, "out of memory", hook.notifications[2].Error)
+}
+
+func Test_Plugin_Restart(t *testing.T) {
+	hook := &webhook{failures: 1}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(Plugin([]string{server.URL}))
+
+	restarted := make(chan error, 1)
+	app.AfterStart(func(_ context.Context) error {
+		go func() {
+			<-app.Ready()
+			restarted <- app.Restart()
+			app.Shutdown(nil)
+		}()
+		return nil
+	})
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, <-restarted)
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+
+	events := make([]string, len(hook.notifications))
+	for i, n := range hook.notifications {
+		events[i] = n.Event
+	}
+
+	// notifications are posted once, rather than once for each time the plugin was initialized
+	require.Equal(t, []string{EventStarted, EventShuttingDown, EventTerminated}, events)
+}
+
+func Test_Plugin_Error(t *testing.T) {
+	hook := &webhook{failures: 1}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(
+		Plugin([]string{server.URL}),
+		&lifecycle.PluginFuncs{
+			RunFunc: func(app *lifecycle.Application) error {
+				return fmt.Errorf("connection refused")
+			},
+		},
+	)
+
+	require.Error(t, app.RunE())
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+
+	require.NotEmpty(t, hook.notifications)
+	require.Equal(t, Notification{
+		Event:  EventError,
+		Time:   hook.notifications[0].Time,
+		State:  "running",
+		Phase:  "run",
+		Plugin: "plugin[1] (*lifecycle.PluginFuncs)",
+		Error:  "connection refused",
+	}, hook.notifications[0])
+}
+
+func Test_Plugin_Overflow(t *testing.T) {
+	hook := &webhook{failures: 1}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+
+	p := Plugin([]string{server.URL}).(*plugin)
+	p.queue = make(chan Notification, queueSize)
+	p.delivered = make(chan struct{})
+	p.abandoned = make(chan struct{})
+
+	// notifications are dropped rather than blocking the lifecycle while none are being delivered
+	for i := 0; i < queueSize+5; i++ {
+		p.enqueue(notification(app, EventError, nil))
+	}
+	require.Equal(t, int32(5), atomic.LoadInt32(&p.dropped))
+
+	go p.deliver(app)
+	p.close(app, notification(app, EventTerminated, nil))
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+
+	require.Len(t, hook.notifications, queueSize+1)
+	require.Equal(t, 5, hook.notifications[0].Dropped)
+	require.Equal(t, EventTerminated, hook.notifications[queueSize].Event)
+}
+
+func Test_Plugin_FlushTimeout(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(
+		Plugin(
+			[]string{server.URL},
+			WithRetries(100),
+			WithBackoff(20*time.Millisecond),
+			WithFlushTimeout(50*time.Millisecond),
+		),
+		&lifecycle.PluginFuncs{
+			RunFunc: func(app *lifecycle.Application) error {
+				return fmt.Errorf("connection refused")
+			},
+		},
+	)
+
+	started := time.Now()
+	require.Error(t, app.RunE())
+	require.Less(t, time.Since(started), time.Second, "termination not bounded by the flush timeout")
+
+	// the notifications still pending are dropped rather than retried
+	time.Sleep(100 * time.Millisecond)
+	dropped := atomic.LoadInt32(&attempts)
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, dropped, atomic.LoadInt32(&attempts))
+}
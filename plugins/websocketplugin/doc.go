@@ -0,0 +1,3 @@
+// Package websocketplugin provides a tracker of open WebSocket connections, asking clients to disconnect when the
+// application is shutdown.
+package websocketplugin
@@ -0,0 +1,208 @@
+package websocketplugin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+const (
+	// DefaultTimeout is how long clients are given to disconnect during shutdown when no timeout is configured.
+	DefaultTimeout = 10 * time.Second
+	// DefaultCloseReason is the reason sent alongside the going away close code.
+	DefaultCloseReason = "server shutting down"
+	// writeTimeout bounds how long sending a close frame to a single client may take.
+	writeTimeout = time.Second
+)
+
+// Tracker tracks open WebSocket connections (see Track).
+type Tracker struct {
+	mu       sync.Mutex
+	conns    map[*websocket.Conn]struct{}
+	draining bool
+	closed   chan struct{}
+}
+
+// New returns a tracker without any connections.
+func New() *Tracker {
+	return &Tracker{conns: make(map[*websocket.Conn]struct{})}
+}
+
+// Track tracks the provided connection, returning a function that must be invoked once the connection has been closed
+// (typically when its read loop returns). Once the tracker is shutting down, the connection is sent a close frame with
+// the going away code and false is returned.
+func (t *Tracker) Track(conn *websocket.Conn) (func(), bool) {
+	t.mu.Lock()
+	if t.draining {
+		t.mu.Unlock()
+		goAway(conn)
+		return func() {}, false
+	}
+
+	if len(t.conns) == 0 {
+		t.closed = make(chan struct{})
+	}
+	t.conns[conn] = struct{}{}
+	t.mu.Unlock()
+
+	once := sync.Once{}
+	return func() {
+		once.Do(func() {
+			t.remove(conn)
+		})
+	}, true
+}
+
+// Len returns the number of connections tracked.
+func (t *Tracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.conns)
+}
+
+// Shutdown sends each connection a close frame with the going away code (websocket.CloseGoingAway) and waits for the
+// connections to be closed. Should the provided context be done first, the remaining connections are closed
+// forcefully.
+func (t *Tracker) Shutdown(ctx context.Context) error {
+	t.mu.Lock()
+	t.draining = true
+	closed := t.closed
+	conns := t.snapshot()
+	t.mu.Unlock()
+
+	if len(conns) == 0 {
+		return nil
+	}
+
+	for _, conn := range conns {
+		goAway(conn)
+	}
+
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		remaining := t.snapshot()
+		t.mu.Unlock()
+
+		for _, conn := range remaining {
+			_ = conn.Close()
+		}
+		return fmt.Errorf("%d websocket connections open: %w", len(remaining), ctx.Err())
+	}
+}
+
+// snapshot returns the tracked connections. The caller must hold the lock.
+func (t *Tracker) snapshot() []*websocket.Conn {
+	conns := make([]*websocket.Conn, 0, len(t.conns))
+	for conn := range t.conns {
+		conns = append(conns, conn)
+	}
+	return conns
+}
+
+func (t *Tracker) remove(conn *websocket.Conn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.conns, conn)
+	if len(t.conns) == 0 && t.closed != nil {
+		close(t.closed)
+		t.closed = nil
+	}
+}
+
+// reset allows the tracker to be reused once the application is restarted.
+func (t *Tracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.draining = false
+}
+
+// goAway asks the client to disconnect. WriteControl is safe to invoke concurrently with the connection's other
+// methods, and failures are ignored since the connection is closed regardless.
+func goAway(conn *websocket.Conn) {
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, DefaultCloseReason)
+	_ = conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(writeTimeout))
+}
+
+// Option configures the plugin.
+type Option func(p *plugin)
+
+// WithTimeout configures how long clients are given to disconnect during shutdown.
+func WithTimeout(timeout time.Duration) Option {
+	return func(p *plugin) {
+		p.timeout = timeout
+	}
+}
+
+// Plugin returns a plugin shutting down the tracker (see Tracker.Shutdown) when the application is drained (see
+// lifecycle.Drainer), closing the connections still open once the timeout (DefaultTimeout unless configured using
+// WithTimeout) is exceeded. Plugins are drained in the reverse order they were registered, so register the plugin after
+// the server accepting the connections.
+func (t *Tracker) Plugin(opts ...Option) lifecycle.Plugin {
+	p := &plugin{
+		tracker: t,
+		timeout: DefaultTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	tracker *Tracker
+	timeout time.Duration
+}
+
+func (p *plugin) Name() string {
+	return "websocket"
+}
+
+func (p *plugin) InitializeContext(_ context.Context, _ *lifecycle.Application) error {
+	p.tracker.reset()
+	return nil
+}
+
+func (p *plugin) RunContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) StartContext(_ context.Context, _ *lifecycle.Application) error {
+	return nil
+}
+
+func (p *plugin) Drain(ctx context.Context, _ *lifecycle.Application) error {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+	return p.tracker.Shutdown(ctx)
+}
+
+func (p *plugin) ShutdownContext(ctx context.Context, app *lifecycle.Application) error {
+	return p.Drain(ctx, app)
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	return p.InitializeContext(app.Context(), app)
+}
+
+func (p *plugin) Shutdown(app *lifecycle.Application) error {
+	return p.ShutdownContext(app.Context(), app)
+}
+
+var _ lifecycle.PluginContext = &plugin{}
+var _ lifecycle.Drainer = &plugin{}
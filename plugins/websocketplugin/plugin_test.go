@@ -0,0 +1,113 @@
+package websocketplugin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+func newServer(tracker *Tracker) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		done, ok := tracker.Track(conn)
+		if !ok {
+			return
+		}
+		defer done()
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+func dial(t *testing.T, server *httptest.Server) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	return conn
+}
+
+func Test_Plugin(t *testing.T) {
+	tracker := New()
+	server := newServer(tracker)
+	defer server.Close()
+
+	client := dial(t, server)
+	defer client.Close()
+
+	for tracker.Len() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the client responds to the close frame, closing the connection
+	closeErr := make(chan error, 1)
+	go func() {
+		for {
+			if _, _, err := client.ReadMessage(); err != nil {
+				closeErr <- err
+				return
+			}
+		}
+	}()
+
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+	app.Initialize(tracker.Plugin())
+
+	go func() {
+		<-app.Ready()
+		app.Shutdown(nil)
+	}()
+
+	require.NoError(t, app.StartE())
+	require.True(t, websocket.IsCloseError(<-closeErr, websocket.CloseGoingAway), "unexpected close")
+	require.Equal(t, 0, tracker.Len())
+
+	// connections are turned away once shutting down
+	late := dial(t, server)
+	defer late.Close()
+
+	_, _, err := late.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "unexpected close")
+}
+
+func Test_Tracker_Timeout(t *testing.T) {
+	tracker := New()
+	server := newServer(tracker)
+	defer server.Close()
+
+	// the client never reads, so it never responds to the close frame
+	client := dial(t, server)
+	defer client.Close()
+
+	for tracker.Len() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := tracker.Shutdown(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.EqualError(t, err, "1 websocket connections open: context deadline exceeded")
+
+	for tracker.Len() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
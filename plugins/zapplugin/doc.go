@@ -0,0 +1,3 @@
+// Package zapplugin provides a plugin that constructs a zap logger, attaches it to the application context, and flushes
+// any buffered log entries when the application is shutdown.
+package zapplugin
@@ -0,0 +1,78 @@
+package zapplugin
+
+import (
+	"context"
+	"errors"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+type contextKey struct{}
+
+func (contextKey) String() string {
+	return "zap logger"
+}
+
+// Plugin returns a plugin that builds a logger from the provided config during initialization and attaches it to the
+// application context. The logger is synced when the application is shutdown.
+func Plugin(config zap.Config, opts ...zap.Option) lifecycle.Plugin {
+	return &plugin{
+		config: config,
+		opts:   opts,
+	}
+}
+
+type plugin struct {
+	lifecycle.PluginFuncs
+	config zap.Config
+	opts   []zap.Option
+	logger *zap.Logger
+}
+
+func (p *plugin) Name() string {
+	return "zap"
+}
+
+func (p *plugin) Initialize(app *lifecycle.Application) error {
+	logger, err := p.config.Build(p.opts...)
+	if err != nil {
+		return err
+	}
+
+	p.logger = logger
+	app.WithValue(contextKey{}, logger)
+	return nil
+}
+
+func (p *plugin) Shutdown(_ *lifecycle.Application) error {
+	if p.logger == nil {
+		return nil
+	}
+	return ignoreUnsyncable(p.logger.Sync())
+}
+
+// FromContext returns the logger attached to the provided context.
+func FromContext(ctx context.Context) (*zap.Logger, bool) {
+	logger, ok := ctx.Value(contextKey{}).(*zap.Logger)
+	return logger, ok
+}
+
+// Logger returns the logger attached to the application context. Should no logger be attached, a no-op logger is
+// returned.
+func Logger(app *lifecycle.Application) *zap.Logger {
+	if logger, ok := FromContext(app.Context()); ok {
+		return logger
+	}
+	return zap.NewNop()
+}
+
+// ignoreUnsyncable drops the errors returned when syncing outputs that cannot be synced, such as terminals.
+func ignoreUnsyncable(err error) error {
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,35 @@
+package zapplugin
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+func Test_Plugin(t *testing.T) {
+	app := lifecycle.NewApplication(lifecycle.WithTerminator(func(err error) {}))
+
+	config := zap.NewProductionConfig()
+	config.OutputPaths = []string{filepath.Join(t.TempDir(), "app.log")}
+
+	app.Initialize(Plugin(config))
+
+	_, ok := app.Plugin("zap")
+	require.True(t, ok, "plugin not registered")
+
+	logger, ok := FromContext(app.Context())
+	require.True(t, ok, "logger not attached")
+	require.Equal(t, logger, Logger(app))
+
+	require.NoError(t, app.RunE())
+}
+
+func Test_Logger_Missing(t *testing.T) {
+	app := lifecycle.NewApplication()
+
+	require.NotNil(t, Logger(app))
+}
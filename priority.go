@@ -0,0 +1,53 @@
+package lifecycle
+
+import (
+	"sort"
+)
+
+// Prioritizer is an optional interface plugins can implement to declare their priority. Plugins provided to the same
+// call to Initialize are initialized (and later started) in order of descending priority, independent of the order
+// they were provided in. Plugins without a priority have a priority of 0 and ties retain the order they were provided
+// in. Plugins are shutdown in the reverse order.
+type Prioritizer interface {
+	Priority() int
+}
+
+// WithPriority wraps the provided plugin, assigning it the provided priority.
+func WithPriority(priority int, plugin Plugin) Plugin {
+	return &priorityPlugin{
+		pluginWrapper: pluginWrapper{plugin},
+		priority:      priority,
+	}
+}
+
+type priorityPlugin struct {
+	pluginWrapper
+	priority int
+}
+
+func (p *priorityPlugin) Priority() int {
+	return p.priority
+}
+
+func priority(plugin Plugin) int {
+	p, ok := findPlugin(plugin, func(p Plugin) bool {
+		_, ok := p.(Prioritizer)
+		return ok
+	})
+	if !ok {
+		return 0
+	}
+	return p.(Prioritizer).Priority()
+}
+
+// prioritize returns a copy of the provided plugins sorted in order of descending priority.
+func prioritize(plugins []Plugin) []Plugin {
+	sorted := make([]Plugin, len(plugins))
+	copy(sorted, plugins)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return priority(sorted[i]) > priority(sorted[j])
+	})
+
+	return sorted
+}
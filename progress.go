@@ -0,0 +1,86 @@
+package lifecycle
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Progress describes a plugin that's about to enter a phase of the lifecycle. This allows CLIs wrapping the application
+// to render the progress of slow startups.
+type Progress struct {
+	// Phase is the phase the plugin is entering.
+	Phase Phase
+	// Plugin is the name of the plugin (see Named).
+	Plugin string
+	// Current is the position of the plugin within the phase, starting at 1.
+	Current int
+	// Total is the number of plugins participating in the phase.
+	Total int
+}
+
+var progressVerbs = map[Phase]string{
+	PhaseInitialize: "initializing",
+	PhaseRun:        "running",
+	PhaseStart:      "starting",
+	PhaseShutdown:   "shutting down",
+}
+
+func (p Progress) String() string {
+	verb, ok := progressVerbs[p.Phase]
+	if !ok {
+		verb = string(p.Phase)
+	}
+	return fmt.Sprintf("plugin %d/%d (%s) %s", p.Current, p.Total, p.Plugin, verb)
+}
+
+// ProgressReporter receives the progress of the application as plugins are initialized, run, started, and shutdown.
+// Reporters are invoked synchronously, before each plugin enters the phase.
+type ProgressReporter interface {
+	ReportProgress(progress Progress)
+}
+
+// ProgressFunc adapts a function to a ProgressReporter.
+type ProgressFunc func(progress Progress)
+
+func (f ProgressFunc) ReportProgress(progress Progress) {
+	f(progress)
+}
+
+// progressTracker reports the progress of plugins through a single phase.
+type progressTracker struct {
+	app     *Application
+	phase   Phase
+	total   int
+	current int32
+}
+
+// trackProgress tracks the progress of the provided registrations through the phase.
+func (app *Application) trackProgress(phase Phase, registrations []*registration) *progressTracker {
+	return &progressTracker{
+		app:   app,
+		phase: phase,
+		total: len(registrations),
+	}
+}
+
+// advance reports the registered plugin entering the phase.
+func (t *progressTracker) advance(reg *registration) {
+	t.app.mu.RLock()
+	reporters := t.app.reporters[:len(t.app.reporters):len(t.app.reporters)]
+	t.app.mu.RUnlock()
+
+	if len(reporters) == 0 {
+		return
+	}
+
+	progress := Progress{
+		Phase:   t.phase,
+		Plugin:  reg.name,
+		Current: int(atomic.AddInt32(&t.current, 1)),
+		Total:   t.total,
+	}
+
+	for _, reporter := range reporters {
+		reporter.ReportProgress(progress)
+	}
+}
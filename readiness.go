@@ -0,0 +1,78 @@
+package lifecycle
+
+import (
+	"time"
+)
+
+// Readiness is an optional interface plugins can implement when returning from Start does not mean the plugin is ready
+// (for example, a server that begins accepting connections in a go-routine). The returned channel is closed once the
+// plugin is ready. The application waits for every started plugin to be ready before completing the start phase.
+type Readiness interface {
+	Ready() <-chan struct{}
+}
+
+// readiness returns the channels of each Readiness in the chain of wrapped plugins.
+func readiness(plugin Plugin) []<-chan struct{} {
+	channels := make([]<-chan struct{}, 0)
+	findPlugin(plugin, func(p Plugin) bool {
+		if r, ok := p.(Readiness); ok {
+			channels = append(channels, r.Ready())
+		}
+		return false
+	})
+	return channels
+}
+
+// Ready returns a channel that's closed once every plugin has been started and reports that it is ready (see
+// Readiness), and the functions registered using AfterStart have completed. The channel is never closed when the
+// application is shutdown before becoming ready. While the application is restarting (see Restart), a channel that's
+// closed once the plugins have been started again is returned.
+func (app *Application) Ready() <-chan struct{} {
+	app.on.Do(app.init)
+	return app.readied()
+}
+
+// readied returns the channel that's closed once the application is ready.
+func (app *Application) readied() chan struct{} {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+
+	return app.ready
+}
+
+// awaitReadiness waits for each started plugin to be ready. Should shutdown be triggered elsewhere, errInterrupted is
+// returned. When configured using WithStartTimeout, a TimeoutError naming the plugins that are not yet ready is
+// returned once the timeout has been exceeded.
+func (app *Application) awaitReadiness() error {
+	pending := make([]*registration, 0)
+	for _, reg := range app.registered() {
+		if reg.getStatus() == statusStarted && len(readiness(reg.plugin)) > 0 {
+			pending = append(pending, reg)
+		}
+	}
+
+	var timeout <-chan time.Time
+	if app.startTimeout > 0 {
+		timer := time.NewTimer(app.startTimeout)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	for i, reg := range pending {
+		for _, ready := range readiness(reg.plugin) {
+			select {
+			case <-ready:
+			case <-app.halt:
+				return errInterrupted
+			case <-timeout:
+				return &TimeoutError{
+					Phase:   PhaseStart,
+					Timeout: app.startTimeout,
+					Plugins: describeRegistrations(pending[i:]),
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package lifecycle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ShutdownKind identifies what triggered the shutdown of an application.
+type ShutdownKind int
+
+const (
+	// ShutdownNone indicates the application has not been shutdown.
+	ShutdownNone ShutdownKind = iota
+	// ShutdownCompleted indicates the application was shutdown after all plugins completed their Run method.
+	ShutdownCompleted
+	// ShutdownSignaled indicates the application was shutdown after receiving an OS signal.
+	ShutdownSignaled
+	// ShutdownFailed indicates the application was shutdown after encountering an error during its lifecycle.
+	ShutdownFailed
+	// ShutdownRequested indicates the application was shutdown by an explicit call to Shutdown.
+	ShutdownRequested
+	// ShutdownCanceled indicates the application was shutdown after its parent context was canceled.
+	ShutdownCanceled
+	// ShutdownUpgraded indicates the application was shutdown after being replaced by an upgraded process (see Upgrade).
+	ShutdownUpgraded
+)
+
+var shutdownKindNames = map[ShutdownKind]string{
+	ShutdownNone:      "none",
+	ShutdownCompleted: "completed",
+	ShutdownSignaled:  "signaled",
+	ShutdownFailed:    "failed",
+	ShutdownRequested: "requested",
+	ShutdownCanceled:  "canceled",
+	ShutdownUpgraded:  "upgraded",
+}
+
+func (k ShutdownKind) String() string {
+	if name, ok := shutdownKindNames[k]; ok {
+		return name
+	}
+	return shutdownKindNames[ShutdownNone]
+}
+
+// ShutdownReason describes why the application was shutdown. This allows operators to distinguish between deploys
+// (signals) and crashes (errors) when reporting on the termination of an application.
+type ShutdownReason struct {
+	// Kind identifies what triggered the shutdown.
+	Kind ShutdownKind
+	// Signal is the signal that triggered the shutdown. Only set when Kind is ShutdownSignaled.
+	Signal os.Signal
+	// Plugin describes the plugin whose error triggered the shutdown. Only set when the error is a PluginError.
+	Plugin string
+	// Phase is the phase the plugin failed in. Only set when the error is a PluginError.
+	Phase Phase
+	// Err is the error provided when the shutdown was triggered, if any.
+	Err error
+}
+
+func (r ShutdownReason) String() string {
+	switch {
+	case r.Signal != nil:
+		return fmt.Sprintf("%s: %s", r.Kind, r.Signal)
+	case r.Err != nil:
+		return fmt.Sprintf("%s: %s", r.Kind, r.Err)
+	default:
+		return r.Kind.String()
+	}
+}
+
+func newShutdownReason(kind ShutdownKind, err error) ShutdownReason {
+	reason := ShutdownReason{
+		Kind: kind,
+		Err:  err,
+	}
+
+	pluginErr := &PluginError{}
+	if errors.As(err, &pluginErr) {
+		reason.Plugin = pluginErr.Plugin
+		reason.Phase = pluginErr.Phase
+	}
+
+	return reason
+}
+
+// ShutdownReason returns why the application was shutdown. The Kind of the returned reason is ShutdownNone while the
+// application is still running.
+func (app *Application) ShutdownReason() ShutdownReason {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+
+	return app.reason
+}
+
+// setReason records why the application was shutdown. Only the first reason is retained.
+func (app *Application) setReason(reason ShutdownReason) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if app.reason.Kind == ShutdownNone {
+		app.reason = reason
+	}
+}
@@ -0,0 +1,213 @@
+package lifecycle
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// pluginStatus tracks how far a plugin has progressed through the lifecycle.
+type pluginStatus int32
+
+const (
+	// statusRegistered marks a plugin that has been registered but not yet initialized.
+	statusRegistered pluginStatus = iota
+	// statusFailed marks a plugin that failed to initialize.
+	statusFailed
+	// statusDisabled marks a plugin that was disabled prior to being initialized (see Enabler).
+	statusDisabled
+	// statusInitialized marks a plugin that successfully initialized.
+	statusInitialized
+	// statusStarted marks a plugin that successfully started.
+	statusStarted
+	// statusShutdown marks a plugin that has been shutdown.
+	statusShutdown
+)
+
+var pluginStatusNames = map[pluginStatus]string{
+	statusRegistered:  "registered",
+	statusFailed:      "failed",
+	statusDisabled:    "disabled",
+	statusInitialized: "initialized",
+	statusStarted:     "started",
+	statusShutdown:    "shutdown",
+}
+
+func (s pluginStatus) String() string {
+	return pluginStatusNames[s]
+}
+
+// registration tracks a plugin registered with the application along with its progress through the lifecycle.
+type registration struct {
+	name         string
+	plugin       Plugin
+	status       int32
+	dependencies []*registration
+}
+
+func (r *registration) getStatus() pluginStatus {
+	return pluginStatus(atomic.LoadInt32(&r.status))
+}
+
+func (r *registration) setStatus(status pluginStatus) {
+	atomic.StoreInt32(&r.status, int32(status))
+}
+
+// initialized returns true when the plugin successfully completed its initialization.
+func (r *registration) initialized() bool {
+	return r.getStatus() >= statusInitialized
+}
+
+// hook returns true when the registration is a function registered using OnShutdown rather than a plugin.
+func (r *registration) hook() bool {
+	_, ok := r.plugin.(*shutdownHook)
+	return ok
+}
+
+// withoutHooks returns the provided registrations excluding functions registered using OnShutdown.
+func withoutHooks(registrations []*registration) []*registration {
+	plugins := make([]*registration, 0, len(registrations))
+	for _, reg := range registrations {
+		if !reg.hook() {
+			plugins = append(plugins, reg)
+		}
+	}
+	return plugins
+}
+
+func (r *registration) String() string {
+	return describePlugin(r.name, r.plugin)
+}
+
+// initializedOnly returns the provided registrations whose plugins successfully initialized.
+func initializedOnly(registrations []*registration) []*registration {
+	initialized := make([]*registration, 0, len(registrations))
+	for _, reg := range registrations {
+		if reg.initialized() {
+			initialized = append(initialized, reg)
+		}
+	}
+	return initialized
+}
+
+// register appends the provided plugins to the application, returning their registrations. Registrations are ordered
+// such that each follows its dependencies. Should any of the plugins already be registered (either the same instance or
+// the same name), none of the plugins are registered and an error wrapping ErrDuplicatePlugin is returned. Similarly,
+// an error is returned when dependencies cannot be resolved.
+func (app *Application) register(plugins []Plugin) ([]*registration, error) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	// shutdown hooks are named separately so they do not affect the generated names of plugins
+	offset := 0
+	for _, reg := range app.plugins {
+		if !reg.hook() {
+			offset++
+		}
+	}
+
+	registrations := make([]*registration, len(plugins))
+	for i, plugin := range plugins {
+		registrations[i] = &registration{
+			name:   pluginName(offset+i, plugin),
+			plugin: plugin,
+		}
+
+		if existing, ok := findDuplicate(registrations[i], app.plugins, registrations[:i]); ok {
+			return nil, fmt.Errorf("%w: %s conflicts with %s", ErrDuplicatePlugin, registrations[i], existing)
+		}
+	}
+
+	if err := resolveDependencies(app.plugins, registrations); err != nil {
+		return nil, err
+	}
+
+	registrations, err := sortDependencies(registrations)
+	if err != nil {
+		return nil, err
+	}
+
+	// generated names reflect the position of the plugin once sorted
+	for i, reg := range registrations {
+		reg.name = pluginName(offset+i, reg.plugin)
+	}
+
+	app.plugins = append(app.plugins, registrations...)
+	return registrations, nil
+}
+
+// findDuplicate returns the first of the provided registrations sharing the name or plugin instance of reg.
+func findDuplicate(reg *registration, groups ...[]*registration) (*registration, bool) {
+	for _, group := range groups {
+		for _, existing := range group {
+			if existing.name == reg.name || samePlugin(existing.plugin, reg.plugin) {
+				return existing, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// samePlugin returns true when both plugins decorate the same instance. Plugins of types that cannot be compared (such
+// as PluginFuncs values) are never considered the same.
+func samePlugin(a, b Plugin) bool {
+	a, b = innermost(a), innermost(b)
+
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) || !t.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// registered returns a snapshot of the plugins registered with the application.
+func (app *Application) registered() []*registration {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+
+	return app.plugins[:len(app.plugins):len(app.plugins)]
+}
+
+// Plugin returns the registered plugin with the provided name. Plugins that do not implement Named can be retrieved
+// using their generated name (such as "plugin[0]"). When the plugin was decorated (for example, using
+// WithShutdownBudget), the decorated plugin is returned.
+func (app *Application) Plugin(name string) (Plugin, bool) {
+	for _, reg := range app.registered() {
+		if reg.name == name && !reg.hook() {
+			return innermost(reg.plugin), true
+		}
+	}
+	return nil, false
+}
+
+// Plugins returns the names of the registered plugins, in the order they are initialized. Plugins that do not implement
+// Named are listed using their generated name (such as "plugin[0]"). Functions registered using OnShutdown are not
+// listed.
+func (app *Application) Plugins() []string {
+	names := make([]string, 0)
+	for _, reg := range app.registered() {
+		if !reg.hook() {
+			names = append(names, reg.name)
+		}
+	}
+	return names
+}
+
+// setCurrent records the plugin currently being initialized, returning the previous one. Plugins may initialize other
+// plugins, so callers restore the previous plugin once complete.
+func (app *Application) setCurrent(reg *registration) *registration {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	previous := app.current
+	app.current = reg
+	return previous
+}
+
+// describeCurrent describes the plugin currently being initialized. Callers must hold the lock.
+func (app *Application) describeCurrent() string {
+	if app.current == nil {
+		return "the application"
+	}
+	return app.current.String()
+}
@@ -0,0 +1,64 @@
+package lifecycle
+
+import (
+	"context"
+)
+
+// Reloader is an optional interface plugins can implement to reload their configuration (such as TLS certificates)
+// without tearing down the application. Reloads are triggered by the signals configured using WithReloadSignals, or
+// programmatically by calling Reload.
+type Reloader interface {
+	Reload(app *Application) error
+}
+
+// Reload invokes the Reload method of each plugin implementing Reloader, in the order they were registered. Errors are
+// reported to the configured hooks and do not shutdown the application. The first error encountered is returned. Once
+// the application has begun shutting down, ErrAlreadyTerminated is returned.
+func (app *Application) Reload() error {
+	app.on.Do(app.init)
+
+	if app.State() >= StateShutdown {
+		return ErrAlreadyTerminated
+	}
+
+	started := app.enter(PhaseReload)
+
+	var first error
+	for _, reg := range app.registered() {
+		if _, ok := findReloader(reg.plugin); !ok || !reg.initialized() {
+			continue
+		}
+
+		err := app.invoke(app.Context(), PhaseReload, 0, reg, reloadPlugin)
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+
+	app.exit(PhaseReload, started, nil)
+	return first
+}
+
+func findReloader(plugin Plugin) (Reloader, bool) {
+	p, ok := findPlugin(plugin, func(p Plugin) bool {
+		_, ok := p.(Reloader)
+		return ok
+	})
+	if !ok {
+		return nil, false
+	}
+	return p.(Reloader), true
+}
+
+func reloadPlugin(_ context.Context, app *Application, plugin Plugin) error {
+	reloader, ok := findReloader(plugin)
+	if !ok {
+		return nil
+	}
+	return reloader.Reload(app)
+}
+
+// reload is the signal handler used to reload the application.
+func reload(app *Application) {
+	_ = app.Reload()
+}
@@ -0,0 +1,124 @@
+package lifecycle
+
+import (
+	"context"
+)
+
+// Restart gracefully shuts down each plugin before initializing and starting them again, without terminating the
+// application. This allows configuration changes that cannot be applied using Reload to take effect. Plugins are
+// restarted using the same order and parallelism used when the application was started, while functions registered
+// using BeforeStart and AfterStart are not invoked again. The application context is replaced, canceling go-routines
+// bound to the previous context (including those managed using Go). Errors encountered while shutting down plugins are
+// reported to the configured hooks. Hooks, interceptors, state listeners, signal handlers, and functions registered
+// using OnShutdown by plugins while they were initialized or started are dropped once the plugins are shutdown, and the
+// functions they deferred (including closing the listeners they opened using Listen) are invoked, since plugins make
+// them again when initialized. The application is not ready (see Ready) until the plugins have been started again.
+// Should a plugin fail to initialize or start, the application is shutdown and the error is returned. Restart returns
+// ErrNotStarted until the application has been started and is ready. Once the application has begun shutting down,
+// ErrAlreadyTerminated is returned.
+func (app *Application) Restart() error {
+	app.on.Do(app.init)
+
+	app.restarting.Lock()
+	defer app.restarting.Unlock()
+
+	if app.isTerminating() {
+		return ErrAlreadyTerminated
+	}
+
+	select {
+	case <-app.Ready():
+	default:
+		return ErrNotStarted
+	}
+
+	started := app.enter(PhaseRestart)
+
+	app.mu.Lock()
+	ready := make(chan struct{})
+	app.ready = ready
+	app.mu.Unlock()
+
+	// the previous context is canceled first so supervisors (see Supervise) do not restart plugins being shutdown
+	app.resetContext()
+	app.stopPlugins()
+
+	// functions registered using OnShutdown are only invoked once the application is shutdown
+	err := app.initializeRegistrations(withoutHooks(app.registered()))
+	if err == nil {
+		err = app.startPlugins()
+	}
+
+	switch {
+	case err == errInterrupted:
+		return ErrAlreadyTerminated
+	case err != nil:
+		app.requestShutdown(newShutdownReason(ShutdownFailed, err))
+		return err
+	}
+
+	close(ready)
+	app.exit(PhaseRestart, started, nil)
+	return nil
+}
+
+// stopPlugins shuts down each initialized plugin in reverse order, releasing the registrations they made (see release)
+// and marking every plugin as registered so they can be initialized again. Functions registered using OnShutdown are
+// only invoked when registered by a plugin.
+func (app *Application) stopPlugins() {
+	registrations := withoutHooks(app.registered())
+	hooks := app.ownedHooks(registrations, PhaseInitialize, PhaseStart)
+
+	// owned hooks retain their position relative to the plugins, so they're invoked before their owner is shutdown
+	plugins := make([]*registration, 0, len(registrations)+len(hooks))
+	for _, reg := range app.registered() {
+		if !reg.hook() || containsRegistration(hooks, reg) {
+			plugins = append(plugins, reg)
+		}
+	}
+	plugins = initializedOnly(plugins)
+
+	ctx := context.WithoutCancel(app.Context())
+
+	// errors are reported to the configured hooks rather than interrupting the shutdown of the remaining plugins
+	_ = schedule(reversed(plugins), app.parallelism, dependentsOf(plugins), func(reg *registration) error {
+		_ = app.invoke(ctx, PhaseShutdown, shutdownBudget(reg.plugin), reg, shutdownPlugin)
+		return nil
+	})
+
+	app.release(registrations, PhaseInitialize, PhaseStart)
+
+	for _, reg := range registrations {
+		reg.setStatus(statusRegistered)
+	}
+}
+
+// containsRegistration returns true when the provided registrations include reg.
+func containsRegistration(registrations []*registration, reg *registration) bool {
+	for _, r := range registrations {
+		if r == reg {
+			return true
+		}
+	}
+	return false
+}
+
+// resetContext replaces the application context, canceling the previous one. Values attached to the previous context
+// remain available.
+func (app *Application) resetContext() {
+	app.mu.Lock()
+	cancel := app.cancel
+	app.context, app.cancel = context.WithCancel(context.WithoutCancel(app.context))
+	app.mu.Unlock()
+
+	cancel()
+}
+
+// cancelContext cancels the application context.
+func (app *Application) cancelContext() {
+	app.mu.RLock()
+	cancel := app.cancel
+	app.mu.RUnlock()
+
+	cancel()
+}
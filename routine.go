@@ -0,0 +1,64 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+)
+
+// Go runs the provided function in a go-routine managed by the application. The function is provided a context that's
+// canceled once the application begins shutting down, and the application waits for the function to return before
+// shutting down its plugins. Should the function return an error, the application is shutdown. Errors caused by the
+// cancellation of the provided context are ignored. Once the application has begun shutting down, calls to Go are
+// ignored.
+func (app *Application) Go(fn func(ctx context.Context) error) {
+	app.on.Do(app.init)
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if app.halted {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(app.context)
+	app.routines.Add(1)
+
+	go func() {
+		defer app.routines.Done()
+		defer cancel()
+
+		go func() {
+			select {
+			case <-app.halt:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		err := fn(ctx)
+		if err == nil || (ctx.Err() != nil && errors.Is(err, context.Canceled)) {
+			return
+		}
+
+		app.requestShutdown(newShutdownReason(ShutdownFailed, err))
+	}()
+}
+
+// haltRoutines cancels the context provided to each go-routine managed by the application and waits for them to
+// return.
+func (app *Application) haltRoutines() {
+	app.stopRoutines()
+	app.routines.Wait()
+}
+
+// stopRoutines cancels the context provided to each go-routine managed by the application without waiting for them to
+// return.
+func (app *Application) stopRoutines() {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if !app.halted {
+		app.halted = true
+		close(app.halt)
+	}
+}
@@ -0,0 +1,156 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// errInterrupted is returned by scheduled invocations to stop scheduling without reporting an error.
+var errInterrupted = errors.New("interrupted")
+
+// scheduled is the outcome of a scheduled invocation.
+type scheduled struct {
+	reg *registration
+	err error
+}
+
+// edges returns the registrations that must complete before the provided registration.
+type edges func(reg *registration) []*registration
+
+// dependenciesOf orders registrations after their dependencies.
+func dependenciesOf(reg *registration) []*registration {
+	return reg.dependencies
+}
+
+// dependentsOf orders the provided registrations after the registrations that depend on them. This is used to shutdown
+// plugins in the reverse order of their dependencies.
+func dependentsOf(registrations []*registration) edges {
+	dependents := make(map[*registration][]*registration, len(registrations))
+	for _, reg := range registrations {
+		for _, dependency := range reg.dependencies {
+			dependents[dependency] = append(dependents[dependency], reg)
+		}
+	}
+
+	return func(reg *registration) []*registration {
+		return dependents[reg]
+	}
+}
+
+// reversed returns a copy of the provided registrations in reverse order.
+func reversed(registrations []*registration) []*registration {
+	reversed := make([]*registration, len(registrations))
+	for i, reg := range registrations {
+		reversed[len(registrations)-1-i] = reg
+	}
+	return reversed
+}
+
+// schedule invokes fn for each of the provided registrations, which must be sorted such that each registration follows
+// the registrations it waits on (as determined by after). Invocations begin once those registrations have completed,
+// with at most limit invocations in-flight at a time. Should an invocation fail, no further invocations are started
+// and the first error is returned once the in-flight invocations complete.
+func schedule(registrations []*registration, limit int, after edges, fn func(reg *registration) error) error {
+	if limit <= 1 {
+		for _, reg := range registrations {
+			if err := fn(reg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	remaining := make(map[*registration]int, len(registrations))
+	dependents := make(map[*registration][]*registration, len(registrations))
+
+	for _, reg := range registrations {
+		remaining[reg] = 0
+	}
+
+	ready := make([]*registration, 0, len(registrations))
+	for _, reg := range registrations {
+		for _, dependency := range after(reg) {
+			if _, ok := remaining[dependency]; ok {
+				remaining[reg]++
+				dependents[dependency] = append(dependents[dependency], reg)
+			}
+		}
+
+		if remaining[reg] == 0 {
+			ready = append(ready, reg)
+		}
+	}
+
+	results := make(chan scheduled, len(registrations))
+	running := 0
+
+	var first error
+	for {
+		for first == nil && running < limit && len(ready) > 0 {
+			reg := ready[0]
+			ready = ready[1:]
+			running++
+
+			go func() {
+				results <- scheduled{reg: reg, err: fn(reg)}
+			}()
+		}
+
+		if running == 0 {
+			return first
+		}
+
+		result := <-results
+		running--
+
+		if result.err != nil {
+			if first == nil {
+				first = result.err
+			}
+			continue
+		}
+
+		for _, dependent := range dependents[result.reg] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+}
+
+// cancelGroup derives contexts that can be canceled together.
+type cancelGroup struct {
+	mu       sync.Mutex
+	cancels  []context.CancelFunc
+	canceled bool
+}
+
+// with derives a context from the provided parent that's canceled along with the group.
+func (g *cancelGroup) with(parent context.Context) context.Context {
+	ctx, cancel := context.WithCancel(parent)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.canceled {
+		cancel()
+	} else {
+		g.cancels = append(g.cancels, cancel)
+	}
+
+	return ctx
+}
+
+// cancel cancels each of the contexts derived from the group, including those derived in the future.
+func (g *cancelGroup) cancel() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.canceled = true
+	for _, cancel := range g.cancels {
+		cancel()
+	}
+	g.cancels = nil
+}
@@ -0,0 +1,398 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// watch waits for the application to be signaled (or its parent context to be canceled) and shuts down each plugin in
+// the reverse order they were initialized. Signals received while running cancel the application context immediately.
+func (app *Application) watch() {
+	signaled := false
+
+	select {
+	case sig := <-app.signal:
+		signaled = true
+		app.setReason(ShutdownReason{Kind: ShutdownSignaled, Signal: sig})
+
+		// cancel the application context so in-flight Run plugins can stop promptly. Plugins are still provided a
+		// detached context during shutdown.
+		if app.State() == StateRunning {
+			app.cancelContext()
+		}
+	case <-app.stop:
+	case <-app.parent.Done():
+		app.setReason(newShutdownReason(ShutdownCanceled, app.parent.Err()))
+	}
+
+	// Run and Start terminate the application once its plugins are shutdown, which is left to the watcher when neither
+	// has been called
+	idle := app.State() == StateInitial
+
+	// continue listening for signals during shutdown when a repeated signal forces the application to quit
+	switch {
+	case app.forceExitCode == 0:
+		signal.Stop(app.signal)
+	case signaled:
+		app.force = app.signal
+	default:
+		app.force = app.repeatedSignal()
+	}
+
+	close(app.terminating)
+
+	// requests may still arrive during the delay, so work is admitted and plugins are left running until it elapses
+	delayed := app.delayShutdown()
+	app.setState(StateShutdown)
+
+	if delayed {
+		app.shutdownPlugins()
+	} else {
+		app.stopRoutines()
+		app.forceErr(NewExitError(app.forceExitCode, ErrForcedShutdown))
+	}
+
+	signal.Stop(app.signal)
+	app.cancelContext()
+	close(app.done)
+
+	if idle {
+		_ = app.terminate()
+	}
+}
+
+// shutdownPlugins shuts down each plugin that successfully initialized in reverse order. Plugins that never initialized
+// (or failed to) are not shutdown. When configured using WithParallelism, independent plugins are shutdown concurrently
+// while plugins are still shutdown before the plugins they depend on. Plugins implementing Drainer are drained (and the
+// work tracked using Track completes) before any plugin is shutdown. Go-routines managed by the application are
+// stopped (and custom phases positioned before PhaseShutdown are run) before any plugin is shutdown, and deferred
+// functions are invoked once every plugin has been shutdown. When a shutdown timeout is configured and the plugins fail
+// to shutdown in time, the application is forcefully terminated and the plugins that were still running are reported.
+// When a plugin declares a shutdown budget, the application stops waiting on the plugin once the budget has been
+// exceeded, allowing later plugins to be shutdown.
+func (app *Application) shutdownPlugins() {
+	// halting go-routines interrupts a restart that's in progress, which completes before plugins are shutdown
+	app.stopRoutines()
+
+	app.restarting.Lock()
+	defer app.restarting.Unlock()
+
+	plugins := initializedOnly(app.registered())
+	progress := app.trackProgress(PhaseShutdown, plugins)
+
+	// the application context may have already been canceled by its parent
+	base := context.WithoutCancel(app.Context())
+
+	ctx, cancel := context.WithCancel(base)
+	var timeout <-chan struct{}
+	force := app.force
+
+	if app.shutdownTimeout > 0 {
+		ctx, cancel = context.WithTimeout(base, app.shutdownTimeout)
+		timeout = ctx.Done()
+	}
+	defer cancel()
+
+	started := app.enter(PhaseShutdown)
+
+	stopWatchdog := app.watchShutdown(started)
+	defer stopWatchdog()
+	complete := make(chan struct{})
+
+	errs := make([]error, 0)
+	errsMu := sync.Mutex{}
+
+	go func() {
+		defer close(complete)
+
+		// work in-flight is given the chance to complete before the resources it depends on are torn down
+		drainErrs := app.drainPlugins(ctx, plugins)
+		app.awaitWork(ctx)
+
+		errsMu.Lock()
+		errs = append(errs, drainErrs...)
+		errsMu.Unlock()
+
+		// managed go-routines may depend on resources provided by plugins and are stopped first
+		app.haltRoutines()
+
+		phaseErrs := app.runShutdownPhases(ctx, plugins)
+
+		errsMu.Lock()
+		errs = append(errs, phaseErrs...)
+		errsMu.Unlock()
+
+		// shutdown errors are collected rather than interrupting the shutdown of the remaining plugins
+		_ = schedule(reversed(plugins), app.parallelism, dependentsOf(plugins), func(reg *registration) error {
+			progress.advance(reg)
+
+			err := app.invoke(ctx, PhaseShutdown, shutdownBudget(reg.plugin), reg, shutdownPlugin)
+			reg.setStatus(statusShutdown)
+
+			if err != nil {
+				errsMu.Lock()
+				errs = append(errs, err)
+				errsMu.Unlock()
+			}
+
+			return nil
+		})
+
+		deferredErrs := app.runDeferred()
+
+		errsMu.Lock()
+		errs = append(errs, deferredErrs...)
+		errsMu.Unlock()
+	}()
+
+	// errors from plugins that fail to shutdown in time are dropped
+	defer func() {
+		errsMu.Lock()
+		defer errsMu.Unlock()
+
+		app.addShutdownErrs(errs...)
+	}()
+
+	select {
+	case <-complete:
+		app.exit(PhaseShutdown, started, nil)
+	case <-timeout:
+		err := &TimeoutError{
+			Phase:   PhaseShutdown,
+			Timeout: app.shutdownTimeout,
+			Plugins: describeRegistrations(pendingShutdown(plugins)),
+		}
+
+		app.exit(PhaseShutdown, started, err)
+		app.addShutdownErrs(err)
+	case <-force:
+		err := NewExitError(app.forceExitCode, ErrForcedShutdown)
+
+		app.exit(PhaseShutdown, started, err)
+		app.forceErr(err)
+	}
+}
+
+// delayShutdown waits for the pre-shutdown delay (see WithPreShutdownDelay) to elapse, returning false should a
+// repeated signal force the application to quit in the meantime.
+func (app *Application) delayShutdown() bool {
+	if app.preShutdownDelay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(app.preShutdownDelay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-app.force:
+		return false
+	}
+}
+
+// repeatedSignal returns a channel receiving the second signal delivered during a shutdown that was not triggered by a
+// signal (such as when using Shutdown), so the first signal an operator sends continues the shutdown gracefully.
+func (app *Application) repeatedSignal() <-chan os.Signal {
+	repeated := make(chan os.Signal, 1)
+
+	go func() {
+		for i := 0; i < 2; i++ {
+			select {
+			case sig := <-app.signal:
+				if i == 1 {
+					repeated <- sig
+				}
+			case <-app.done:
+				return
+			}
+		}
+	}()
+	return repeated
+}
+
+// pendingShutdown returns the provided registrations that have yet to be shutdown.
+func pendingShutdown(registrations []*registration) []*registration {
+	pending := make([]*registration, 0, len(registrations))
+	for _, reg := range registrations {
+		if reg.getStatus() != statusShutdown {
+			pending = append(pending, reg)
+		}
+	}
+	return pending
+}
+
+// ShutdownBudgeter is an optional interface plugins can implement to declare the amount of time they are allowed to
+// spend shutting down. This prevents a single misbehaving plugin from consuming the entire shutdown timeout and
+// starving later plugins of cleanup time.
+type ShutdownBudgeter interface {
+	ShutdownBudget() time.Duration
+}
+
+// WithShutdownBudget wraps the provided plugin, limiting the amount of time it's allowed to spend shutting down.
+func WithShutdownBudget(budget time.Duration, plugin Plugin) Plugin {
+	return &shutdownBudgetPlugin{
+		pluginWrapper: pluginWrapper{plugin},
+		budget:        budget,
+	}
+}
+
+type shutdownBudgetPlugin struct {
+	pluginWrapper
+	budget time.Duration
+}
+
+func (p *shutdownBudgetPlugin) ShutdownBudget() time.Duration {
+	return p.budget
+}
+
+func shutdownBudget(plugin Plugin) time.Duration {
+	p, ok := findPlugin(plugin, func(p Plugin) bool {
+		_, ok := p.(ShutdownBudgeter)
+		return ok
+	})
+	if !ok {
+		return 0
+	}
+	return p.(ShutdownBudgeter).ShutdownBudget()
+}
+
+// Shutdown triggers a graceful shutdown of the application. This allows plugins and application code to shutdown the
+// application programmatically (for example, after detecting an unrecoverable condition) rather than sending the
+// process a signal. The provided error is reported as the reason the application terminated and may be nil. Shutdown
+// does not wait for plugins to be shutdown. Instead, the blocked call to Run or Start returns once complete. Shutdown
+// is idempotent and safe to call concurrently. Once the application has terminated, calls to Shutdown are ignored.
+func (app *Application) Shutdown(err error) {
+	app.on.Do(app.init)
+	app.requestShutdown(newShutdownReason(ShutdownRequested, err))
+}
+
+// requestShutdown records the reason for the shutdown and triggers the shutdown of the application.
+func (app *Application) requestShutdown(reason ShutdownReason) {
+	if app.State() == StateTerminated {
+		return
+	}
+
+	app.setErr(reason.Err)
+	app.setReason(reason)
+
+	// fast path for when shutdown has already been triggered
+	if app.State() >= StateShutdown {
+		return
+	}
+
+	app.stopping.Do(func() {
+		close(app.stop)
+	})
+}
+
+// shutdown triggers the shutdown of the application, waits for all plugins to be shutdown, and returns the error that
+// caused the application to terminate.
+func (app *Application) shutdown(err error) error {
+	kind := ShutdownCompleted
+	if err != nil {
+		kind = ShutdownFailed
+	}
+
+	app.requestShutdown(newShutdownReason(kind, err))
+	<-app.done
+
+	return app.terminate()
+}
+
+// terminate marks the application as terminated and returns the error that caused the termination. The terminated
+// phase is only reported once, regardless of how many times terminate is called.
+func (app *Application) terminate() error {
+	app.finalize.Do(func() {
+		app.setState(StateTerminated)
+		app.report(newEvent(EventPhaseEnter, PhaseTerminated, "", app.created, app.Err()))
+
+		close(app.terminated)
+	})
+
+	return app.Err()
+}
+
+// Terminating returns a channel that's closed as soon as the application begins shutting down, ahead of the delay
+// configured using WithPreShutdownDelay. Readiness checks use it to fail while plugins continue to serve requests (and
+// work is still admitted using Track) during the delay. Once the delay has elapsed, the application transitions to
+// StateShutdown.
+func (app *Application) Terminating() <-chan struct{} {
+	app.on.Do(app.init)
+	return app.terminating
+}
+
+// isTerminating returns true once the application has begun shutting down (see Terminating).
+func (app *Application) isTerminating() bool {
+	select {
+	case <-app.terminating:
+		return true
+	default:
+		return false
+	}
+}
+
+// Done returns a channel that's closed once the application has finished shutting down each of its plugins. Mirroring
+// context.Context, this allows go-routines outside of the plugin system to coordinate with application teardown.
+func (app *Application) Done() <-chan struct{} {
+	app.on.Do(app.init)
+	return app.done
+}
+
+// Wait blocks until the application has terminated and returns the error that caused the termination (nil if the
+// application was shutdown cleanly). This provides a way to join an application started in another go-routine. An
+// application shutdown before Run or Start is called terminates once its plugins have been shutdown.
+func (app *Application) Wait() error {
+	app.on.Do(app.init)
+
+	<-app.terminated
+	return app.Err()
+}
+
+// Err returns the error that caused the application to terminate, or nil if the application was shutdown cleanly. Any
+// errors encountered while shutting down plugins are joined with the error that caused the shutdown. The error is only
+// final once the application has terminated (for example, after Wait returns).
+func (app *Application) Err() error {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+
+	if len(app.shutdownErrs) == 0 {
+		return app.err
+	}
+
+	return errors.Join(append([]error{app.err}, app.shutdownErrs...)...)
+}
+
+// addShutdownErrs records errors encountered while shutting down plugins.
+func (app *Application) addShutdownErrs(errs ...error) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.shutdownErrs = append(app.shutdownErrs, errs...)
+}
+
+// setErr records the error that caused the application to terminate. Only the first error is retained.
+func (app *Application) setErr(err error) {
+	if err == nil {
+		return
+	}
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if app.err == nil {
+		app.err = err
+	}
+}
+
+// forceErr records the error that caused the application to terminate, replacing any previously recorded error.
+func (app *Application) forceErr(err error) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.err = err
+}
@@ -0,0 +1,49 @@
+package lifecycle
+
+import (
+	"os"
+	"os/signal"
+)
+
+// SignalHandler is invoked when the application receives the signal it was registered for.
+type SignalHandler func(app *Application)
+
+// HandleSignal registers a handler that's invoked each time the application receives the provided signal. This allows
+// signals like SIGUSR1 and SIGUSR2 to be mapped to custom behaviors (such as dumping state or rotating logs) while the
+// shutdown signals retain their semantics. Handlers are invoked sequentially from a single go-routine and stop being
+// invoked once the application has been shutdown.
+func (app *Application) HandleSignal(sig os.Signal, handler SignalHandler) {
+	app.on.Do(app.init)
+	app.handleSignal(sig, handler)
+}
+
+func (app *Application) handleSignal(sig os.Signal, handler SignalHandler) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if _, ok := app.handlers[sig]; !ok {
+		signal.Notify(app.custom, sig)
+	}
+
+	app.handlers[sig] = append(app.handlers[sig], owned[SignalHandler]{value: handler, by: app.owner()})
+}
+
+// watchSignals dispatches the signals the application receives to their registered handlers.
+func (app *Application) watchSignals() {
+	defer signal.Stop(app.custom)
+
+	for {
+		select {
+		case sig := <-app.custom:
+			app.mu.RLock()
+			handlers := valuesOf(app.handlers[sig])
+			app.mu.RUnlock()
+
+			for _, handler := range handlers {
+				handler(app)
+			}
+		case <-app.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,249 @@
+//go:build !windows
+// +build !windows
+
+package lifecycle
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ApplicationWithSignals(t *testing.T) {
+	app := NewApplication(WithSignals(syscall.SIGUSR1))
+	require.Equal(t, []os.Signal{syscall.SIGUSR1}, app.signals, "unexpected signals")
+
+	app = NewApplication()
+	require.Equal(t, []os.Signal{syscall.SIGTERM, syscall.SIGINT}, app.signals, "unexpected default signals")
+}
+
+func Test_ApplicationStart_Signal(t *testing.T) {
+	app := NewApplication(
+		WithSignals(syscall.SIGUSR1),
+		WithTerminator(func(err error) {
+			require.NoError(t, err, "application unexpectedly failed with error")
+		}),
+	)
+
+	counts, executionCountPlugin := countingPlugin()
+
+	app.Initialize(
+		executionCountPlugin,
+		&PluginFuncs{
+			StartFunc: func(app *Application) error {
+				go func() {
+					app.WithValue(ContextKey("signaled"), true)
+					_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+				}()
+				return nil
+			},
+		},
+	)
+
+	app.Start()
+
+	require.Equal(t, 1, counts[start], "unexpected start count")
+	require.Equal(t, 1, counts[shutdown], "unexpected shutdown count")
+	require.Equal(t, true, app.Context().Value(ContextKey("signaled")))
+
+	reason := app.ShutdownReason()
+	require.Equal(t, ShutdownSignaled, reason.Kind)
+	require.Equal(t, syscall.SIGUSR1, reason.Signal)
+}
+
+func Test_ApplicationRun_Signal(t *testing.T) {
+	app := NewApplication(
+		WithSignals(syscall.SIGUSR1),
+		WithTerminator(func(err error) {}),
+	)
+
+	counts, executionCountPlugin := countingPlugin()
+
+	app.Initialize(
+		executionCountPlugin,
+		&PluginFuncs{
+			RunFunc: func(app *Application) error {
+				_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+
+				select {
+				case <-app.Context().Done():
+					return nil
+				case <-time.After(time.Second):
+					return errors.New("context not canceled")
+				}
+			},
+		},
+	)
+
+	require.NoError(t, app.RunE())
+	require.Equal(t, 1, counts[shutdown], "unexpected shutdown count")
+	require.Equal(t, ShutdownSignaled, app.ShutdownReason().Kind)
+}
+
+func Test_ApplicationWithForceQuit(t *testing.T) {
+	app := NewApplication(
+		WithSignals(syscall.SIGUSR2),
+		WithForceQuit(130),
+		WithTerminator(func(err error) {
+			require.Fail(t, "terminator unexpectedly invoked")
+		}),
+	)
+
+	blocked := make(chan struct{})
+	defer close(blocked)
+
+	app.Initialize(
+		&PluginFuncs{
+			StartFunc: func(app *Application) error {
+				return syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+			},
+			ShutdownFunc: func(app *Application) error {
+				_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+				<-blocked
+				return nil
+			},
+		},
+	)
+
+	err := app.StartE()
+	require.True(t, errors.Is(err, ErrForcedShutdown), "unexpected error")
+	require.Equal(t, 130, ExitCode(err))
+}
+
+func Test_ApplicationWithForceQuit_Shutdown(t *testing.T) {
+	app := NewApplication(
+		WithSignals(syscall.SIGUSR2),
+		WithForceQuit(130),
+		WithTerminator(func(err error) {
+			require.Fail(t, "terminator unexpectedly invoked")
+		}),
+	)
+
+	blocked := make(chan struct{})
+	defer close(blocked)
+
+	// the shutdown was not triggered by a signal, so only the second signal forces the application to quit
+	continued := make(chan struct{})
+	app.Initialize(
+		&PluginFuncs{
+			StartFunc: func(app *Application) error {
+				app.Shutdown(nil)
+				return nil
+			},
+			ShutdownFunc: func(app *Application) error {
+				_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+				time.Sleep(50 * time.Millisecond)
+				close(continued)
+
+				_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+				<-blocked
+				return nil
+			},
+		},
+	)
+
+	err := app.StartE()
+	require.ErrorIs(t, err, ErrForcedShutdown)
+	require.Equal(t, 130, ExitCode(err))
+
+	select {
+	case <-continued:
+	default:
+		require.Fail(t, "first signal forced the application to quit")
+	}
+}
+
+type reloadingPlugin struct {
+	PluginFuncs
+	reloads int
+}
+
+func (p *reloadingPlugin) Reload(app *Application) error {
+	p.reloads++
+	app.Shutdown(nil)
+	return nil
+}
+
+func Test_ApplicationReload(t *testing.T) {
+	app := NewApplication(
+		WithReloadSignals(syscall.SIGHUP),
+		WithTerminator(func(err error) {
+			require.NoError(t, err, "application unexpectedly failed with error")
+		}),
+	)
+
+	plugin := &reloadingPlugin{
+		PluginFuncs: PluginFuncs{
+			StartFunc: func(app *Application) error {
+				return syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+			},
+		},
+	}
+
+	app.Initialize(WithShutdownBudget(time.Second, plugin))
+	app.Start()
+
+	require.Equal(t, 1, plugin.reloads, "unexpected reload count")
+	require.Equal(t, StateTerminated, app.State())
+}
+
+func Test_ApplicationHandleSignal(t *testing.T) {
+	handled := 0
+
+	app := newTestApp(func(err error) {
+		require.NoError(t, err, "application unexpectedly failed with error")
+	})
+
+	app.HandleSignal(syscall.SIGUSR1, func(app *Application) {
+		handled++
+		app.Shutdown(nil)
+	})
+
+	app.Initialize(
+		&PluginFuncs{
+			StartFunc: func(app *Application) error {
+				return syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+			},
+		},
+	)
+
+	app.Start()
+
+	require.Equal(t, 1, handled, "unexpected handled count")
+}
+
+func Test_ApplicationWithPreShutdownDelay_ForceQuit(t *testing.T) {
+	app := NewApplication(
+		WithSignals(syscall.SIGUSR2),
+		WithForceQuit(130),
+		WithPreShutdownDelay(time.Hour),
+		WithTerminator(func(err error) {
+			require.Fail(t, "terminator unexpectedly invoked")
+		}),
+	)
+
+	shutdown := false
+	app.Initialize(&PluginFuncs{
+		StartFunc: func(app *Application) error {
+			go func() {
+				_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+				<-app.Terminating()
+				_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+			}()
+			return nil
+		},
+		ShutdownFunc: func(app *Application) error {
+			shutdown = true
+			return nil
+		},
+	})
+
+	err := app.StartE()
+	require.ErrorIs(t, err, ErrForcedShutdown)
+	require.Equal(t, 130, ExitCode(err))
+	require.False(t, shutdown, "plugin shutdown during the delay")
+}
@@ -0,0 +1,94 @@
+package lifecycle
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// State defines a series of states that the given system may be in.
+type State int32
+
+const (
+	// StateInvalid marks when a system falls into an invalid state (unused).
+	StateInvalid State = iota
+	// StateInitial marks when a system is in the initial state and can accept plugins for initialization.
+	StateInitial
+	// StateRunning indicates the Run method has been invoked and the application is currently executing.
+	StateRunning
+	// StateStarted indicates the Start method has been invoked and the application is continuously executing.
+	StateStarted
+	// StateShutdown indicates the application is going into a terminated state, either due to an error or signal.
+	StateShutdown
+	// StateTerminated indicates the application is no longer running and typically set prior to exit.
+	StateTerminated
+)
+
+var stateNames = map[State]string{
+	StateInvalid:    "invalid",
+	StateInitial:    "initial",
+	StateRunning:    "running",
+	StateStarted:    "started",
+	StateShutdown:   "shutdown",
+	StateTerminated: "terminated",
+}
+
+func (s State) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+	return stateNames[StateInvalid]
+}
+
+// State returns the current state of the application. Plugins can use this to gate behavior on the current phase of
+// the lifecycle.
+func (app *Application) State() State {
+	app.on.Do(app.init)
+	return State(atomic.LoadInt32((*int32)(&app.state)))
+}
+
+func (app *Application) setState(state State) {
+	previous := State(atomic.SwapInt32((*int32)(&app.state), int32(state)))
+	if previous != state {
+		app.changed(previous, state)
+	}
+}
+
+// transition moves the application from one state to another, returning false if it was not in the expected state.
+func (app *Application) transition(from, to State) bool {
+	if !atomic.CompareAndSwapInt32((*int32)(&app.state), int32(from), int32(to)) {
+		return false
+	}
+
+	app.changed(from, to)
+	return true
+}
+
+// StateListener is invoked when the application transitions from one state to another.
+type StateListener func(from, to State)
+
+// OnStateChange registers a listener that's invoked each time the application transitions between states. This allows
+// components (such as a readiness endpoint) to react to the application being started or shutdown. Listeners are
+// invoked synchronously, in the order they were registered.
+func (app *Application) OnStateChange(listener StateListener) {
+	app.on.Do(app.init)
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.listeners = append(app.listeners, owned[StateListener]{value: listener, by: app.owner()})
+}
+
+func (app *Application) changed(from, to State) {
+	app.history.add(Record{
+		Time:       time.Now(),
+		Transition: &Transition{From: from, To: to},
+	})
+
+	app.mu.RLock()
+	listeners := valuesOf(app.listeners)
+	app.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(from, to)
+	}
+}
@@ -0,0 +1,76 @@
+package lifecycle
+
+import (
+	"fmt"
+	"time"
+)
+
+// PluginStatus describes the progress of a registered plugin through the lifecycle.
+type PluginStatus struct {
+	// Name is the name of the plugin (see Plugins).
+	Name string `json:"name"`
+	// Type is the type of the plugin, excluding any decorators.
+	Type string `json:"type"`
+	// Status is how far the plugin has progressed: "registered", "failed", "disabled", "initialized", "started", or
+	// "shutdown".
+	Status string `json:"status"`
+	// Phases are the phases the plugin takes part in, including the optional phases of the interfaces it implements
+	// (such as Reloader or Drainer) and the custom phases of the application when it implements PhaseHandler.
+	Phases []Phase `json:"phases"`
+	// Timings is how long the plugin most recently spent in each phase it completed (see Timings).
+	Timings map[Phase]time.Duration `json:"timings,omitempty"`
+}
+
+// PluginStatuses returns the status of each registered plugin, in the order they are initialized. This allows
+// operators to see which plugins are running and how long each took to initialize, start, or shutdown.
+func (app *Application) PluginStatuses() []PluginStatus {
+	registrations := withoutHooks(app.registered())
+	timings := app.Timings()
+
+	statuses := make([]PluginStatus, len(registrations))
+	for i, reg := range registrations {
+		statuses[i] = PluginStatus{
+			Name:   reg.name,
+			Type:   fmt.Sprintf("%T", innermost(reg.plugin)),
+			Status: reg.getStatus().String(),
+			Phases: app.pluginPhases(reg.plugin),
+		}
+
+		description := reg.String()
+		for _, timing := range timings {
+			if timing.Plugin != description {
+				continue
+			}
+
+			if statuses[i].Timings == nil {
+				statuses[i].Timings = make(map[Phase]time.Duration)
+			}
+			statuses[i].Timings[timing.Phase] = timing.Duration
+		}
+	}
+	return statuses
+}
+
+// pluginPhases returns the phases the provided plugin takes part in, in the order they're run.
+func (app *Application) pluginPhases(plugin Plugin) []Phase {
+	phases := make([]Phase, 0)
+	if validates(plugin) {
+		phases = append(phases, PhaseValidate)
+	}
+
+	phases = append(phases, PhaseInitialize, PhaseRun, PhaseStart)
+	if _, ok := findReloader(plugin); ok {
+		phases = append(phases, PhaseReload)
+	}
+	if _, ok := findDrainer(plugin); ok {
+		phases = append(phases, PhaseDrain)
+	}
+	phases = append(phases, PhaseShutdown)
+
+	if _, ok := findPhaseHandler(plugin); ok {
+		for _, custom := range app.phases {
+			phases = append(phases, custom.phase)
+		}
+	}
+	return phases
+}
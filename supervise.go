@@ -0,0 +1,222 @@
+package lifecycle
+
+import (
+	"context"
+	"time"
+)
+
+// Exiter is an optional interface plugins can implement when their Start method launches background work (such as the
+// accept loop of a server). The returned channel receives the result of the work once it exits, where a nil error
+// indicates the work completed. Exited is invoked each time the plugin is started. Unless the plugin is supervised (see
+// Supervise), the application is shutdown should the work fail.
+type Exiter interface {
+	Exited() <-chan error
+}
+
+// RestartMode determines when a supervised plugin is restarted.
+type RestartMode int
+
+const (
+	// RestartNever never restarts the plugin. Should its background work fail, the application is shutdown.
+	RestartNever RestartMode = iota
+	// RestartOnFailure restarts the plugin when its background work fails.
+	RestartOnFailure
+	// RestartAlways restarts the plugin whenever its background work exits, even when it completes.
+	RestartAlways
+)
+
+// RestartPolicy describes how the application supervises a plugin once it has been started.
+type RestartPolicy struct {
+	// Mode determines when the plugin is restarted.
+	Mode RestartMode
+	// Backoff is how long the application waits before restarting the plugin the first time. The delay doubles after
+	// each consecutive restart. Defaults to one second.
+	Backoff time.Duration
+	// MaxBackoff bounds the delay between restarts. Defaults to one minute.
+	MaxBackoff time.Duration
+}
+
+func (p RestartPolicy) restarts(err error) bool {
+	return p.Mode == RestartAlways || (p.Mode == RestartOnFailure && err != nil)
+}
+
+// limits returns the initial and maximum delay between restarts.
+func (p RestartPolicy) limits() (time.Duration, time.Duration) {
+	backoff, limit := p.Backoff, p.MaxBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	if limit <= 0 {
+		limit = time.Minute
+	}
+	return backoff, limit
+}
+
+// backoff returns the delay preceding the provided attempt.
+func (p RestartPolicy) backoff(attempt int) time.Duration {
+	backoff, limit := p.limits()
+	for i := 0; i < attempt && backoff < limit; i++ {
+		backoff *= 2
+	}
+
+	if backoff > limit {
+		return limit
+	}
+	return backoff
+}
+
+// Supervised is an optional interface plugins can implement to declare how they should be restarted when their
+// background work exits (see Exiter).
+type Supervised interface {
+	RestartPolicy() RestartPolicy
+}
+
+// Supervise wraps the provided plugin, restarting it according to the provided policy when its background work exits
+// instead of shutting down the application. Restarting a plugin shuts it down before starting it again.
+func Supervise(policy RestartPolicy, plugin Plugin) Plugin {
+	return &supervisedPlugin{
+		pluginWrapper: pluginWrapper{plugin},
+		policy:        policy,
+	}
+}
+
+type supervisedPlugin struct {
+	pluginWrapper
+	policy RestartPolicy
+}
+
+func (p *supervisedPlugin) RestartPolicy() RestartPolicy {
+	return p.policy
+}
+
+func restartPolicy(plugin Plugin) RestartPolicy {
+	p, ok := findPlugin(plugin, func(p Plugin) bool {
+		_, ok := p.(Supervised)
+		return ok
+	})
+	if !ok {
+		return RestartPolicy{}
+	}
+	return p.(Supervised).RestartPolicy()
+}
+
+func findExiter(plugin Plugin) (Exiter, bool) {
+	p, ok := findPlugin(plugin, func(p Plugin) bool {
+		_, ok := p.(Exiter)
+		return ok
+	})
+	if !ok {
+		return nil, false
+	}
+	return p.(Exiter), true
+}
+
+// supervise watches the background work of the started plugin using a go-routine managed by the application,
+// restarting the plugin according to its RestartPolicy. Failures are reported to the configured hooks as an
+// EventWarning when the plugin is restarted. Supervision stops once the application is shutdown or restarted.
+func (app *Application) supervise(reg *registration) {
+	exiter, ok := findExiter(reg.plugin)
+	if !ok {
+		return
+	}
+
+	s := &supervisor{
+		app:    app,
+		reg:    reg,
+		exiter: exiter,
+		policy: restartPolicy(reg.plugin),
+	}
+
+	app.Go(s.run)
+}
+
+// supervisor restarts a plugin according to its RestartPolicy.
+type supervisor struct {
+	app     *Application
+	reg     *registration
+	exiter  Exiter
+	policy  RestartPolicy
+	attempt int
+}
+
+func (s *supervisor) run(ctx context.Context) error {
+	for {
+		started := time.Now()
+
+		var err error
+		select {
+		case err = <-s.exiter.Exited():
+		case <-ctx.Done():
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return nil // the plugin is being shutdown or restarted
+		}
+
+		err = wrapPluginError(PhaseStart, s.reg, err)
+		if !s.policy.restarts(err) {
+			return err
+		}
+
+		// plugins that remained up for longer than the maximum backoff are no longer considered to be failing
+		if _, limit := s.policy.limits(); time.Since(started) > limit {
+			s.attempt = 0
+		}
+
+		if !s.restart(ctx, err) {
+			return nil
+		}
+	}
+}
+
+// restart restarts the plugin until it starts successfully, returning false should the application be shutdown or
+// restarted in the meantime.
+func (s *supervisor) restart(ctx context.Context, err error) bool {
+	for {
+		s.app.report(newEvent(EventWarning, PhaseStart, s.reg.String(), time.Now(), err))
+
+		restarted, startErr := s.app.restartPlugin(ctx, s.reg, s.policy.backoff(s.attempt))
+		s.attempt++
+
+		if !restarted || startErr == nil {
+			return restarted
+		}
+		err = startErr
+	}
+}
+
+// restartPlugin shuts down the registered plugin and starts it again once the provided backoff has elapsed, releasing
+// the registrations the plugin made while it was started (see release). The plugin is not restarted when the
+// application is shutdown or restarted in the meantime.
+func (app *Application) restartPlugin(ctx context.Context, reg *registration, backoff time.Duration) (bool, error) {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return false, nil
+	}
+
+	if !app.restarting.TryLock() {
+		return false, nil // the application is being shutdown or restarted
+	}
+	defer app.restarting.Unlock()
+
+	if ctx.Err() != nil {
+		return false, nil
+	}
+
+	// registrations made while the plugin was started are released, since it makes them again once started
+	plugins := []*registration{reg}
+	for _, hook := range reversed(app.ownedHooks(plugins, PhaseStart)) {
+		_ = app.invoke(ctx, PhaseShutdown, shutdownBudget(hook.plugin), hook, shutdownPlugin)
+	}
+
+	_ = app.invoke(ctx, PhaseShutdown, shutdownBudget(reg.plugin), reg, shutdownPlugin)
+	app.release(plugins, PhaseStart)
+
+	return true, app.invoke(ctx, PhaseStart, app.startTimeout, reg, startPlugin)
+}
@@ -0,0 +1,56 @@
+package lifecycle
+
+import (
+	"context"
+	"time"
+)
+
+// pluginFunc invokes a single phase of the provided plugin.
+type pluginFunc func(ctx context.Context, app *Application, plugin Plugin) error
+
+// invokeWithin invokes the phase of the registered plugin. Errors returned by the plugin are wrapped in a PluginError.
+// When a timeout is provided, the application stops waiting on the plugin once the timeout has been exceeded and a
+// TimeoutError naming the plugin is returned. Plugins implementing PluginContext receive the deadline through the
+// provided context.
+func (app *Application) invokeWithin(
+	ctx context.Context, phase Phase, timeout time.Duration, reg *registration, invoke pluginFunc,
+) error {
+	if timeout <= 0 {
+		return wrapPluginError(phase, reg, invoke(ctx, app, reg.plugin))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- wrapPluginError(phase, reg, invoke(ctx, app, reg.plugin))
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		return &TimeoutError{
+			Phase:   phase,
+			Timeout: timeout,
+			Plugins: []string{reg.String()},
+		}
+	}
+}
+
+// wrapPluginError associates the error returned by the registered plugin with the plugin and phase it occurred in.
+func wrapPluginError(phase Phase, reg *registration, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &PluginError{
+		Plugin: reg.String(),
+		Phase:  phase,
+		Err:    err,
+	}
+}
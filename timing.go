@@ -0,0 +1,74 @@
+package lifecycle
+
+import (
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// Timing records how long a plugin spent in a phase of the lifecycle.
+type Timing struct {
+	// Plugin describes the plugin that was invoked.
+	Plugin string
+	// Phase is the phase the plugin was invoked for.
+	Phase Phase
+	// StartedAt is when the plugin was invoked.
+	StartedAt time.Time
+	// Duration is how long the plugin took to complete the phase.
+	Duration time.Duration
+}
+
+// Timings returns how long each plugin spent in each phase, in the order the plugins completed them. This allows
+// developers to determine which plugins make the application slow to boot or shutdown. Only the most recent timing of
+// each plugin and phase is retained (for example, when the application is reloaded or restarted).
+func (app *Application) Timings() []Timing {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+
+	timings := make([]Timing, len(app.timings))
+	copy(timings, app.timings)
+	return timings
+}
+
+// recordTiming records how long the plugin described by the provided event spent in its phase, replacing the timing
+// previously recorded for the same plugin and phase so the timings do not grow for the life of the process.
+func (app *Application) recordTiming(event Event) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	for i, timing := range app.timings {
+		if timing.Plugin == event.Plugin && timing.Phase == event.Phase {
+			app.timings = append(app.timings[:i], app.timings[i+1:]...)
+			break
+		}
+	}
+
+	app.timings = append(app.timings, Timing{
+		Plugin:    event.Plugin,
+		Phase:     event.Phase,
+		StartedAt: event.StartedAt,
+		Duration:  event.Duration,
+	})
+}
+
+// logTimings logs the timings recorded while starting the application, slowest first, when configured using
+// WithTimingSummary.
+func (app *Application) logTimings() {
+	if !app.timingSummary {
+		return
+	}
+
+	timings := app.Timings()
+	sort.SliceStable(timings, func(i, j int) bool {
+		return timings[i].Duration > timings[j].Duration
+	})
+
+	logger := app.Logger()
+	for _, timing := range timings {
+		logger.Info("plugin timing",
+			slog.String("phase", string(timing.Phase)),
+			slog.String("plugin", timing.Plugin),
+			slog.Duration("duration", timing.Duration),
+		)
+	}
+}
@@ -0,0 +1,69 @@
+package lifecycle
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// listenersEnv describes the listeners passed to an upgraded process, which begin at file descriptor 3.
+	listenersEnv = "LIFECYCLE_LISTENERS"
+	// readyEnv holds the file descriptor an upgraded process writes to once it is ready.
+	readyEnv = "LIFECYCLE_READY_FD"
+
+	// defaultUpgradeTimeout is how long an upgraded process is given to become ready when no timeout is configured.
+	defaultUpgradeTimeout = time.Minute
+)
+
+// ErrUpgradeInProgress is returned when the application is upgraded while a previous upgrade has yet to complete.
+var ErrUpgradeInProgress = errors.New("upgrade already in progress")
+
+// inheritedListener describes a listener passed to an upgraded process.
+type inheritedListener struct {
+	Network string `json:"network"`
+	Address string `json:"address"`
+}
+
+// Upgrade replaces the running process with a new one, started from the current executable (which may have been
+// replaced on disk) using the same arguments, without dropping connections. The listeners owned by the application
+// (see Listen) are passed to the new process, where calls to Listen with the same network and address return the
+// inherited listener rather than binding a new one. Once the new process has started and is ready (see Ready), the
+// application is shutdown, draining its plugins, with ShutdownUpgraded as the reason. Should the new process exit or
+// fail to become ready within the configured timeout (see WithUpgradeTimeout), it is killed and an error is returned,
+// leaving the application running. Upgrade returns ErrNotStarted until the application has been started and is ready,
+// and ErrAlreadyTerminated once the application has begun shutting down. Upgrades are not supported on Windows.
+func (app *Application) Upgrade() error {
+	app.on.Do(app.init)
+
+	if !app.upgrading.TryLock() {
+		return ErrUpgradeInProgress
+	}
+	defer app.upgrading.Unlock()
+
+	if app.isTerminating() {
+		return ErrAlreadyTerminated
+	}
+
+	select {
+	case <-app.Ready():
+	default:
+		return ErrNotStarted
+	}
+
+	if err := app.upgrade(); err != nil {
+		return err
+	}
+
+	app.requestShutdown(ShutdownReason{Kind: ShutdownUpgraded})
+	return nil
+}
+
+// Upgrader is a SignalHandler that upgrades the application (see Upgrade), allowing a signal to trigger an in-place
+// upgrade. Failures are reported to the configured hooks as an EventWarning.
+//
+//	app.HandleSignal(syscall.SIGUSR2, lifecycle.Upgrader)
+func Upgrader(app *Application) {
+	if err := app.Upgrade(); err != nil {
+		app.report(newEvent(EventWarning, "", "", time.Now(), err))
+	}
+}
@@ -0,0 +1,126 @@
+//go:build !windows
+// +build !windows
+
+package lifecycle
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// upgradeNetworkEnv provides the upgraded test process the network it was passed a listener for.
+	upgradeNetworkEnv = "LIFECYCLE_TEST_UPGRADE_NETWORK"
+	// upgradeAddrEnv provides the upgraded test process the address it was passed a listener for.
+	upgradeAddrEnv = "LIFECYCLE_TEST_UPGRADE_ADDR"
+)
+
+func Test_ApplicationUpgrade(t *testing.T) {
+	// reserve a free port for the application to listen on
+	reserved, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := reserved.Addr().String()
+	require.NoError(t, reserved.Close())
+
+	testUpgrade(t, "tcp", addr)
+}
+
+func Test_ApplicationUpgrade_Unix(t *testing.T) {
+	// unix socket paths are limited in length, so avoid the long paths of t.TempDir
+	dir, err := os.MkdirTemp("", "upgrade")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the socket is not removed when the application closes the listener passed to the upgraded process
+	testUpgrade(t, "unix", filepath.Join(dir, "upgrade.sock"))
+}
+
+// testUpgrade upgrades an application listening on the provided address, and verifies the upgraded process accepts
+// connections on it once the application has shutdown.
+func testUpgrade(t *testing.T, network, addr string) {
+	command := upgradeCommand
+	defer func() { upgradeCommand = command }()
+
+	upgradeCommand = func() (string, []string, error) {
+		return os.Args[0], []string{"-test.run=^Test_ApplicationUpgrade_Process$"}, nil
+	}
+
+	t.Setenv(upgradeNetworkEnv, network)
+	t.Setenv(upgradeAddrEnv, addr)
+
+	app := NewApplication(WithTerminator(func(err error) {}), WithUpgradeTimeout(10*time.Second))
+	app.Initialize(&PluginFuncs{
+		InitializeFunc: func(app *Application) error {
+			_, err := app.Listen(network, addr)
+			return err
+		},
+	})
+
+	require.ErrorIs(t, app.Upgrade(), ErrNotStarted)
+
+	upgraded := make(chan error, 1)
+	go func() {
+		<-app.Ready()
+		upgraded <- app.Upgrade()
+	}()
+
+	require.NoError(t, app.StartE())
+	require.NoError(t, <-upgraded)
+	require.Equal(t, ShutdownUpgraded, app.ShutdownReason().Kind)
+
+	// the upgraded process continues accepting connections on the inherited listener
+	conn, err := net.Dial(network, addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.SetDeadline(time.Now().Add(10*time.Second)))
+	reply, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	require.Equal(t, "upgraded", string(reply))
+}
+
+// Test_ApplicationUpgrade_Process is run as the process started by Test_ApplicationUpgrade.
+func Test_ApplicationUpgrade_Process(t *testing.T) {
+	if _, ok := os.LookupEnv(listenersEnv); !ok {
+		t.Skip("only run as an upgraded process")
+	}
+
+	app := NewApplication(WithTerminator(func(err error) {}))
+	inherited := app.Listeners()
+	require.Len(t, inherited, 1)
+
+	app.Initialize(&PluginFuncs{
+		StartFunc: func(app *Application) error {
+			listener, err := app.Listen(os.Getenv(upgradeNetworkEnv), os.Getenv(upgradeAddrEnv))
+			if err != nil {
+				return err
+			}
+			require.Same(t, inherited[0], listener)
+
+			app.Go(func(ctx context.Context) error {
+				defer app.Shutdown(nil)
+
+				deadliner := listener.(*managedListener).Listener.(interface{ SetDeadline(time.Time) error })
+				_ = deadliner.SetDeadline(time.Now().Add(10 * time.Second))
+				conn, err := listener.Accept()
+				if err != nil {
+					return err
+				}
+				defer conn.Close()
+
+				_, err = io.WriteString(conn, "upgraded")
+				return err
+			})
+			return nil
+		},
+	})
+
+	require.NoError(t, app.StartE())
+}
@@ -0,0 +1,182 @@
+//go:build !windows
+// +build !windows
+
+package lifecycle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// upgradeCommand returns the executable and arguments an upgraded process is started with.
+var upgradeCommand = func() (string, []string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", nil, err
+	}
+	return exe, os.Args[1:], nil
+}
+
+// upgrade starts a new process, passing it the open listeners, and waits for it to become ready.
+func (app *Application) upgrade() error {
+	exe, args, err := upgradeCommand()
+	if err != nil {
+		return err
+	}
+
+	listeners := app.openListeners()
+	specs := make([]inheritedListener, len(listeners))
+	files := make([]*os.File, 0, len(listeners)+1)
+
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+
+	for i, l := range listeners {
+		filer, ok := l.Listener.(interface{ File() (*os.File, error) })
+		if !ok {
+			return fmt.Errorf("cannot pass %s listener on %s to upgraded process", l.network, l.address)
+		}
+
+		f, err := filer.File()
+		if err != nil {
+			return err
+		}
+
+		files = append(files, f)
+		specs[i] = inheritedListener{Network: l.network, Address: l.address}
+	}
+
+	encoded, err := json.Marshal(specs)
+	if err != nil {
+		return err
+	}
+
+	ready, notify, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer ready.Close()
+
+	files = append(files, notify)
+
+	cmd := exec.Command(exe, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(os.Environ(),
+		listenersEnv+"="+string(encoded),
+		readyEnv+"="+strconv.Itoa(3+len(listeners)),
+	)
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// only the new process should hold the write end, so its exit unblocks the read
+	_ = notify.Close()
+	files = files[:len(files)-1]
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	readied := make(chan error, 1)
+	go func() {
+		_, err := ready.Read(make([]byte, 1))
+		readied <- err
+	}()
+
+	timeout := app.upgradeTimeout
+	if timeout <= 0 {
+		timeout = defaultUpgradeTimeout
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-readied:
+		if err == nil {
+			keepSockets(listeners)
+			return nil
+		}
+
+		if err := <-exited; err != nil {
+			return fmt.Errorf("upgraded process exited before becoming ready: %w", err)
+		}
+		return errors.New("upgraded process exited before becoming ready")
+	case <-timer.C:
+		_ = cmd.Process.Kill()
+		return fmt.Errorf("upgraded process did not become ready within %s", timeout)
+	}
+}
+
+// keepSockets prevents the unix sockets passed to an upgraded process from being removed when this process closes its
+// listeners, since the upgraded process continues to accept connections on them.
+func keepSockets(listeners []*managedListener) {
+	for _, l := range listeners {
+		if unix, ok := l.Listener.(*net.UnixListener); ok {
+			unix.SetUnlinkOnClose(false)
+		}
+	}
+}
+
+// inheritListeners adopts the listeners passed to the process by the application it was upgraded from, and notifies
+// that application once this one is ready. The environment is cleared so the processes it starts do not inherit it.
+// False is returned when the process was not started by Upgrade.
+func (app *Application) inheritListeners() bool {
+	encoded, ok := os.LookupEnv(listenersEnv)
+	if !ok {
+		return false
+	}
+
+	fd, err := strconv.Atoi(os.Getenv(readyEnv))
+	_ = os.Unsetenv(listenersEnv)
+	_ = os.Unsetenv(readyEnv)
+
+	var specs []inheritedListener
+	err = errors.Join(err, json.Unmarshal([]byte(encoded), &specs))
+	if err != nil {
+		app.report(newEvent(EventWarning, "", "", time.Now(), fmt.Errorf("invalid inherited listeners: %w", err)))
+		return true
+	}
+
+	for i, spec := range specs {
+		f := os.NewFile(uintptr(3+i), spec.Address)
+		listener, err := net.FileListener(f)
+		_ = f.Close()
+
+		if err != nil {
+			app.report(newEvent(EventWarning, "", "", time.Now(), fmt.Errorf("failed to inherit %s listener on %s: %w",
+				spec.Network, spec.Address, err)))
+			continue
+		}
+
+		managed := newManagedListener(listener, spec.Network, spec.Address)
+		managed.inherited = true
+
+		app.sockets = append(app.sockets, managed)
+		app.deferred = append(app.deferred, owned[func() error]{value: managed.Close})
+	}
+
+	notify := os.NewFile(uintptr(fd), "ready")
+	go func() {
+		defer notify.Close()
+
+		select {
+		case <-app.readied():
+			_, _ = notify.Write([]byte{1})
+		case <-app.terminated:
+		}
+	}()
+	return true
+}
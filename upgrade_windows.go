@@ -0,0 +1,15 @@
+package lifecycle
+
+import (
+	"errors"
+)
+
+// upgrade is not supported on windows, which cannot pass listeners to a new process.
+func (app *Application) upgrade() error {
+	return errors.New("upgrades are not supported on windows")
+}
+
+// inheritListeners is a no-op on windows, since listeners are never passed to an upgraded process.
+func (app *Application) inheritListeners() bool {
+	return false
+}
@@ -0,0 +1,64 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+)
+
+// Validator is an optional interface plugins can implement to validate their configuration before any plugin is
+// initialized. Every plugin is validated, allowing misconfiguration to be reported for all plugins at once rather than
+// failing on the first plugin and tearing down the application. Since no plugin has been initialized yet, Validate
+// should not depend on values provided by other plugins. Plugins disabled when validated (see Enabler) are skipped,
+// so conditions that depend on values provided by other plugins are not met until those plugins are initialized.
+type Validator interface {
+	Validate(app *Application) error
+}
+
+// validates returns true when the plugin (or any plugin it wraps) implements Validator.
+func validates(plugin Plugin) bool {
+	_, ok := findPlugin(plugin, func(p Plugin) bool {
+		_, ok := p.(Validator)
+		return ok
+	})
+	return ok
+}
+
+// validatePlugin validates each Validator in the chain of wrapped plugins.
+func validatePlugin(_ context.Context, app *Application, plugin Plugin) error {
+	errs := make([]error, 0)
+	findPlugin(plugin, func(p Plugin) bool {
+		if v, ok := p.(Validator); ok {
+			errs = append(errs, v.Validate(app))
+		}
+		return false
+	})
+	return errors.Join(errs...)
+}
+
+// validate validates each of the enabled registered plugins implementing Validator, joining the errors they return.
+// The validate phase is only reported when at least one enabled plugin implements Validator.
+func (app *Application) validate(registrations []*registration) error {
+	validators := make([]*registration, 0, len(registrations))
+	for _, reg := range registrations {
+		if validates(reg.plugin) && enabled(app, reg.plugin) {
+			validators = append(validators, reg)
+		}
+	}
+
+	if len(validators) == 0 {
+		return nil
+	}
+
+	started := app.enter(PhaseValidate)
+
+	errs := make([]error, 0, len(validators))
+	for _, reg := range validators {
+		errs = append(errs, app.invoke(app.Context(), PhaseValidate, 0, reg, validatePlugin))
+	}
+
+	err := errors.Join(errs...)
+	if err == nil {
+		app.exit(PhaseValidate, started, nil)
+	}
+	return err
+}
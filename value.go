@@ -0,0 +1,70 @@
+package lifecycle
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// typeKey is the context key used to store values by their type.
+type typeKey[T any] struct{}
+
+func (typeKey[T]) String() string {
+	return "type " + typeName[T]()
+}
+
+// typeName returns the name of the provided type, including interface types.
+func typeName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
+
+// Set attaches the provided value to the application context, keyed by its type. This allows plugins to exchange
+// dependencies without type assertions or string keys. Setting a value replaces any previous value of the same type.
+func Set[T any](app *Application, value T) {
+	app.WithValue(typeKey[T]{}, value)
+}
+
+// Get returns the value of the provided type attached to the application context using Set. The boolean reports
+// whether a value was found.
+func Get[T any](app *Application) (T, bool) {
+	value, ok := app.Context().Value(typeKey[T]{}).(T)
+	return value, ok
+}
+
+// MustGet is like Get, but panics with a descriptive message when no value of the provided type has been attached to
+// the application context.
+func MustGet[T any](app *Application) T {
+	return MustValue[T](app, typeKey[T]{})
+}
+
+// MustValue returns the value attached to the application context using the provided key. Should the value be missing
+// (or not be of the provided type), MustValue panics with a message naming the key, the plugin that provided the value
+// (if any), and the plugin that requested it. This replaces opaque panics caused by failed type assertions.
+func MustValue[T any](app *Application, key interface{}) T {
+	raw := app.Context().Value(key)
+
+	value, ok := raw.(T)
+	if !ok {
+		panic(app.describeMissing(key, raw, typeName[T]()))
+	}
+
+	return value
+}
+
+// describeMissing describes why the value for the provided key could not be used.
+func (app *Application) describeMissing(key, raw interface{}, expected string) string {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+
+	if raw != nil {
+		return fmt.Sprintf("lifecycle: value for %v provided by %s is %T, not %s",
+			key, app.providers[key], raw, expected)
+	}
+
+	msg := fmt.Sprintf("lifecycle: no value provided for %v", key)
+	if app.current != nil {
+		msg += fmt.Sprintf(" (requested by %s during initialize, was the plugin providing it initialized first?)",
+			app.current)
+	}
+
+	return msg
+}
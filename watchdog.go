@@ -0,0 +1,110 @@
+package lifecycle
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Watchdog configures how the application reacts to a shutdown that takes longer than expected. Hung shutdowns are a
+// common cause of deployments stalling while the old process refuses to exit.
+type Watchdog struct {
+	// Threshold is how long the application may spend shutting down before the watchdog fires.
+	Threshold time.Duration
+	// Output receives a dump of the application (see Dump) when the watchdog fires. Defaults to os.Stderr.
+	Output io.Writer
+	// ExitCode, when non-zero, exits the process using the code once the dump has been written.
+	ExitCode int
+}
+
+// invocation describes a plugin phase that's in progress.
+type invocation struct {
+	phase     Phase
+	startedAt time.Time
+}
+
+// track records the registered plugin as being in the provided phase until the returned function is called.
+func (app *Application) track(phase Phase, reg *registration, startedAt time.Time) func() {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.inflight[reg] = invocation{phase: phase, startedAt: startedAt}
+
+	return func() {
+		app.mu.Lock()
+		defer app.mu.Unlock()
+
+		delete(app.inflight, reg)
+	}
+}
+
+// inflightIn describes the plugins currently in the provided phase.
+func (app *Application) inflightIn(phase Phase) []string {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+
+	plugins := make([]string, 0, len(app.inflight))
+	for reg, invocation := range app.inflight {
+		if invocation.phase == phase {
+			plugins = append(plugins, reg.String())
+		}
+	}
+
+	sort.Strings(plugins)
+	return plugins
+}
+
+// watchShutdown fires the shutdown watchdog, when configured, should shutdown take longer than its threshold. The
+// returned function stops the watchdog.
+func (app *Application) watchShutdown(startedAt time.Time) func() {
+	watchdog := app.shutdownWatchdog
+	if watchdog.Threshold <= 0 {
+		return func() {}
+	}
+
+	timer := time.AfterFunc(watchdog.Threshold, func() {
+		plugins := app.inflightIn(PhaseShutdown)
+		app.report(newEvent(EventWarning, PhaseShutdown, strings.Join(plugins, ", "), startedAt, &SlowError{
+			Phase:   PhaseShutdown,
+			Elapsed: time.Since(startedAt),
+			Plugins: plugins,
+		}))
+
+		output := watchdog.Output
+		if output == nil {
+			output = os.Stderr
+		}
+
+		_ = app.Dump(output)
+
+		if watchdog.ExitCode != 0 {
+			os.Exit(watchdog.ExitCode)
+		}
+	})
+
+	return func() {
+		timer.Stop()
+	}
+}
+
+// watchStartup reports a SlowError to the configured hooks, when configured using WithStartupWatchdog, should the
+// registered plugin take longer than the threshold to initialize or start. The returned function stops the watchdog.
+func (app *Application) watchStartup(phase Phase, reg *registration, startedAt time.Time) func() {
+	if app.startupThreshold <= 0 || (phase != PhaseInitialize && phase != PhaseStart) {
+		return func() {}
+	}
+
+	timer := time.AfterFunc(app.startupThreshold, func() {
+		app.report(newEvent(EventWarning, phase, reg.String(), startedAt, &SlowError{
+			Phase:   phase,
+			Elapsed: time.Since(startedAt),
+			Plugins: []string{reg.String()},
+		}))
+	})
+
+	return func() {
+		timer.Stop()
+	}
+}
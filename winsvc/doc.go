@@ -0,0 +1,4 @@
+// Package winsvc adapts a lifecycle.Application so that it can be run as a Windows service. Service control requests
+// to stop or shutdown the service trigger a graceful shutdown of the application. On all other platforms, this package
+// is empty.
+package winsvc
@@ -0,0 +1,10 @@
+module github.com/effxhq/go-lifecycle/winsvc
+
+go 1.21
+
+require (
+	github.com/effxhq/go-lifecycle v0.2.0
+	golang.org/x/sys v0.17.0
+)
+
+replace github.com/effxhq/go-lifecycle => ..
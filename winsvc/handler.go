@@ -0,0 +1,54 @@
+//go:build windows
+// +build windows
+
+package winsvc
+
+import (
+	"golang.org/x/sys/windows/svc"
+
+	"github.com/effxhq/go-lifecycle"
+)
+
+const accepts = svc.AcceptStop | svc.AcceptShutdown
+
+// Handler implements svc.Handler, starting the application when the service is started and shutting it down when the
+// service is stopped.
+type Handler struct {
+	App *lifecycle.Application
+}
+
+// Execute starts the application and services change requests until the application terminates.
+func (h *Handler) Execute(_ []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- h.App.StartE()
+	}()
+
+	changes <- svc.Status{State: svc.Running, Accepts: accepts}
+
+	for {
+		select {
+		case err := <-result:
+			code := uint32(lifecycle.ExitCode(err))
+			return code != 0, code
+
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				changes <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				h.App.Shutdown(nil)
+			}
+		}
+	}
+}
+
+var _ svc.Handler = &Handler{}
+
+// Run runs the application as the named Windows service, blocking until the service is stopped.
+func Run(name string, app *lifecycle.Application) error {
+	return svc.Run(name, &Handler{App: app})
+}
@@ -0,0 +1,104 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// work counts the units of work in-flight across the application (see Track).
+type work struct {
+	mu       sync.Mutex
+	inFlight int
+	idle     chan struct{}
+}
+
+// Track counts a unit of work (such as a request or a job) as in-flight, returning a function marking the work
+// complete. Once the application is shutdown (after the delay configured using WithPreShutdownDelay), new work is
+// rejected and false is returned. When the application is shutdown, it waits for the work in-flight to complete
+// (bounded by the timeout configured using WithWorkTimeout) before any plugin is shutdown. This generalizes draining
+// requests to any work plugins depend on.
+func (app *Application) Track() (func(), bool) {
+	app.on.Do(app.init)
+
+	app.work.mu.Lock()
+	defer app.work.mu.Unlock()
+
+	// the state is checked while holding the lock so work cannot be admitted once awaitWork has observed the count
+	if app.State() >= StateShutdown {
+		return func() {}, false
+	}
+
+	app.work.inFlight++
+	if app.work.inFlight == 1 {
+		app.work.idle = make(chan struct{})
+	}
+
+	once := sync.Once{}
+	return func() {
+		once.Do(app.work.done)
+	}, true
+}
+
+// done marks a unit of work as complete.
+func (w *work) done() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.inFlight--
+	if w.inFlight == 0 {
+		close(w.idle)
+	}
+}
+
+// InFlight returns the number of units of work currently in-flight (see Track).
+func (app *Application) InFlight() int {
+	app.on.Do(app.init)
+
+	app.work.mu.Lock()
+	defer app.work.mu.Unlock()
+
+	return app.work.inFlight
+}
+
+// Idle returns a channel closed once no work is in-flight (see Track). The channel returned while no work is in-flight
+// is already closed.
+func (app *Application) Idle() <-chan struct{} {
+	app.on.Do(app.init)
+
+	app.work.mu.Lock()
+	defer app.work.mu.Unlock()
+
+	if app.work.inFlight == 0 {
+		idle := make(chan struct{})
+		close(idle)
+		return idle
+	}
+	return app.work.idle
+}
+
+// awaitWork waits for the work in-flight to complete, the provided context to be done, or the work timeout to be
+// exceeded. Work that fails to complete in time is reported to the configured hooks as an EventWarning.
+func (app *Application) awaitWork(ctx context.Context) {
+	idle := app.Idle()
+
+	select {
+	case <-idle:
+		return
+	default:
+	}
+
+	if app.workTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, app.workTimeout)
+		defer cancel()
+	}
+
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		err := fmt.Errorf("%d units of work in-flight: %w", app.InFlight(), ctx.Err())
+		app.report(newEvent(EventWarning, PhaseShutdown, "", time.Now(), err))
+	}
+}